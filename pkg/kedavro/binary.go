@@ -0,0 +1,174 @@
+package kedavro
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ouzi-dev/avro-kedavro/pkg/types"
+)
+
+// appendBinary appends the avro binary encoding of the native value of a field
+func appendBinary(buf []byte, field *Field, value interface{}) ([]byte, error) {
+	switch field.Type {
+	case types.Union:
+		return appendUnionBinary(buf, field, value)
+	case types.Primitive:
+		return appendPrimitiveBinary(buf, field, field.TypeValue.(string), value)
+	default:
+		return nil, fmt.Errorf("unknown field type in field %s", field.Name)
+	}
+}
+
+func appendUnionBinary(buf []byte, field *Field, value interface{}) ([]byte, error) {
+	unionTypes := field.TypeValue.([]interface{})
+
+	branch := types.NilType
+	if value != nil {
+		branch = getUnionValueType(field)
+		union, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot encode value \"%v\" of field \"%s\" as \"union\"", value, field.Name)
+		}
+		value = union[branch]
+	}
+
+	for i, v := range unionTypes {
+		if v == branch {
+			buf = appendLong(buf, int64(i))
+			break
+		}
+	}
+
+	if value == nil {
+		return buf, nil
+	}
+
+	return appendBinary(buf, getUnionBranchField(field, branch), value)
+}
+
+// nolint gocyclo
+func appendPrimitiveBinary(buf []byte, field *Field, typeName string, value interface{}) ([]byte, error) {
+	var ok bool
+	switch typeName {
+	case types.NilType:
+		return buf, nil
+	case types.BoolType:
+		var v bool
+		if v, ok = value.(bool); ok {
+			if v {
+				return append(buf, 1), nil
+			}
+			return append(buf, 0), nil
+		}
+	case types.IntType:
+		var v int32
+		if v, ok = value.(int32); ok {
+			return appendLong(buf, int64(v)), nil
+		}
+	case types.LongType:
+		if field.LogicalType == types.TimestampMillis || field.LogicalType == types.TimestampMicros {
+			var t time.Time
+			if t, ok = value.(time.Time); ok {
+				return appendLong(buf, getTimestampAsLong(field, t)), nil
+			}
+			break
+		}
+		var v int64
+		if v, ok = value.(int64); ok {
+			return appendLong(buf, v), nil
+		}
+	case types.FloatType:
+		var v float32
+		if v, ok = value.(float32); ok {
+			buf = append(buf, 0, 0, 0, 0)
+			binary.LittleEndian.PutUint32(buf[len(buf)-4:], math.Float32bits(v))
+			return buf, nil
+		}
+	case types.DoubleType:
+		var v float64
+		if v, ok = value.(float64); ok {
+			buf = append(buf, 0, 0, 0, 0, 0, 0, 0, 0)
+			binary.LittleEndian.PutUint64(buf[len(buf)-8:], math.Float64bits(v))
+			return buf, nil
+		}
+	case types.StringType:
+		var v string
+		if v, ok = value.(string); ok {
+			return append(appendLong(buf, int64(len(v))), v...), nil
+		}
+	case types.BytesType:
+		var v []byte
+		if v, ok = value.([]byte); ok {
+			return append(appendLong(buf, int64(len(v))), v...), nil
+		}
+	case types.RecordType:
+		var v map[string]interface{}
+		if v, ok = value.(map[string]interface{}); ok {
+			return appendRecordBinary(buf, field, v)
+		}
+	case types.MapType:
+		var v map[string]interface{}
+		if v, ok = value.(map[string]interface{}); ok {
+			return appendMapBinary(buf, field, v)
+		}
+	}
+
+	return nil, fmt.Errorf("cannot encode value \"%v\" of field \"%s\" as \"%s\"", value, field.Name, typeName)
+}
+
+func appendRecordBinary(buf []byte, field *Field, record map[string]interface{}) ([]byte, error) {
+	var err error
+	for _, v := range field.Fields {
+		if buf, err = appendBinary(buf, v, record[v.Name]); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+// appendMapBinary encodes the map in one block, with the keys sorted so the same
+// map has always the same encoding
+func appendMapBinary(buf []byte, field *Field, values map[string]interface{}) ([]byte, error) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	if len(keys) > 0 {
+		buf = appendLong(buf, int64(len(keys)))
+	}
+	for _, k := range keys {
+		v, ok := values[k].(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot encode value \"%v\" of key \"%s\" in field \"%s\" as \"string\"", values[k], k, field.Name)
+		}
+		buf = append(appendLong(buf, int64(len(k))), k...)
+		buf = append(appendLong(buf, int64(len(v))), v...)
+	}
+
+	return appendLong(buf, 0), nil
+}
+
+// appendLong appends a long with zig-zag and variable length encoding, ints are
+// encoded the same way
+func appendLong(buf []byte, value int64) []byte {
+	encoded := (uint64(value) << 1) ^ uint64(value>>63)
+	for encoded >= 0x80 {
+		buf = append(buf, byte(encoded)|0x80)
+		encoded >>= 7
+	}
+	return append(buf, byte(encoded))
+}
+
+// getTimestampAsLong converts a timestamp to milliseconds or microseconds, the
+// same way goavro does
+func getTimestampAsLong(field *Field, t time.Time) int64 {
+	if field.LogicalType == types.TimestampMillis {
+		return t.UnixNano() / int64(time.Millisecond)
+	}
+	return t.Unix()*1e6 + int64(t.Nanosecond()/1e3)
+}
@@ -0,0 +1,200 @@
+package kedavro
+
+import (
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+const binarySchema = `
+{
+	"name": "Wizard",
+	"type": "record",
+	"fields": [
+		{"name": "name", "type": "string"},
+		{"name": "alive", "type": "boolean"},
+		{"name": "wand", "type": "bytes"},
+		{"name": "power", "type": "float"},
+		{"name": "score", "type": "double"},
+		{"name": "age", "type": "int"},
+		{"name": "points", "type": "long"},
+		{"name": "nothing", "type": "null"},
+		{"name": "born", "type": "long", "logicalType": "timestamp-millis"},
+		{"name": "seen", "type": {"type": "long", "logicalType": "timestamp-micros"}},
+		{"name": "house", "type": ["null", "string"]},
+		{"name": "pet", "type": ["string", "null"], "default": "owl"},
+		{"name": "friends", "type": ["null", "long"]},
+		{
+			"name": "address",
+			"type": "record",
+			"default": {"street": "privet drive", "number": 4},
+			"fields": [
+				{"name": "street", "type": "string"},
+				{"name": "number", "type": "int"}
+			]
+		},
+		{
+			"name": "school",
+			"type": "record",
+			"fields": [
+				{"name": "name", "type": "string"},
+				{"name": "year", "type": ["null", "int"]}
+			]
+		},
+		{
+			"name": "extra",
+			"type": {"type": "map", "values": "string"},
+			"kedavro.catchAll": true
+		}
+	]
+}
+`
+
+func TestParseToBinary(t *testing.T) {
+	codec, err := goavro.NewCodec(binarySchema)
+	assert.NoError(t, err)
+
+	p, err := NewParser(binarySchema, WithStringToNumber())
+	assert.NoError(t, err)
+
+	records := []string{
+		`{"name": "harry", "alive": true, "wand": "holly", "power": 1.5, "score": -1234.5678, "age": -17, "points": 9223372036854775807, "nothing": null, "born": 1571128870123, "seen": 1571128870123456, "house": "gryffindor", "pet": null, "friends": -2, "school": {"name": "hogwarts", "year": 7}}`,
+		`{"name": "", "alive": false, "wand": "", "power": 0, "score": 0, "age": 2147483647, "points": "-9223372036854775808", "nothing": null, "born": 0, "seen": -1, "house": null, "friends": null, "address": {"street": "", "number": -2147483648}, "school": {"name": "hogwarts", "year": null}}`,
+		`{"name": "ron", "alive": true, "wand": "willow", "power": 3.4028234663852886e+38, "score": 1e-300, "age": 11, "points": 1, "nothing": null, "born": -1571128870123, "seen": 1571128870123456, "house": "gryffindor", "friends": 0, "school": {"name": "hogwarts", "year": 1}, "owl": "pigwidgeon"}`,
+	}
+
+	for _, v := range records {
+		native, err := p.Parse([]byte(v))
+		assert.NoError(t, err, v)
+
+		expected, err := codec.BinaryFromNative(nil, native)
+		assert.NoError(t, err, v)
+
+		prefix := []byte("prefix")
+		result, err := p.ParseToBinary(prefix, []byte(v))
+		assert.NoError(t, err, v)
+		assert.Equal(t, append([]byte("prefix"), expected...), result, v)
+	}
+}
+
+func TestParseToBinaryCatchAll(t *testing.T) {
+	codec, err := goavro.NewCodec(binarySchema)
+	assert.NoError(t, err)
+
+	p, err := NewParser(binarySchema)
+	assert.NoError(t, err)
+
+	record := `{"name": "ron", "alive": true, "wand": "willow", "power": 1, "score": 1, "age": 11, "points": 1, "nothing": null, "born": 1, "seen": 1, "house": null, "friends": null, "school": {"name": "hogwarts", "year": 1}, "owl": "pigwidgeon", "rat": {"name": "scabbers"}, "brothers": 5}`
+
+	native, err := p.Parse([]byte(record))
+	assert.NoError(t, err)
+
+	result, err := p.ParseToBinary(nil, []byte(record))
+	assert.NoError(t, err)
+
+	// goavro encodes maps in random order, so we compare the decoded values
+	decoded, rest, err := codec.NativeFromBinary(result)
+	assert.NoError(t, err)
+	assert.Empty(t, rest)
+	assert.Equal(t, native.(map[string]interface{})["extra"], decoded.(map[string]interface{})["extra"])
+
+	// and the keys are sorted, so the encoding of the same record is always the same
+	again, err := p.ParseToBinary(nil, []byte(record))
+	assert.NoError(t, err)
+	assert.Equal(t, result, again)
+}
+
+func TestParseToBinaryErrorPolicies(t *testing.T) {
+	schema := `
+	{
+		"name": "Order",
+		"type": "record",
+		"fields": [
+			{"name": "id", "type": "long"},
+			{
+				"name": "shipping",
+				"type": "record",
+				"default": {"method": "standard", "days": 5},
+				"kedavro.onError": "default",
+				"fields": [
+					{"name": "method", "type": "string"},
+					{"name": "days", "type": "int"}
+				]
+			},
+			{"name": "coupon", "type": ["null", "string"], "kedavro.onError": "null"}
+		]
+	}
+	`
+
+	codec, err := goavro.NewCodec(schema)
+	assert.NoError(t, err)
+
+	p, err := NewParser(schema)
+	assert.NoError(t, err)
+
+	// the nested record is replaced by its default after encoding "express"
+	record := `{"id": 1, "shipping": {"method": "express", "days": "tomorrow"}, "coupon": 10}`
+
+	native, err := p.Parse([]byte(record))
+	assert.NoError(t, err)
+
+	expected, err := codec.BinaryFromNative(nil, native)
+	assert.NoError(t, err)
+
+	result, err := p.ParseToBinary(nil, []byte(record))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+
+	// records with errors don't add anything to the buffer
+	result, err = p.ParseToBinary([]byte("prefix"), []byte(`{"id": "one"}`))
+	assert.Error(t, err)
+	assert.Equal(t, []byte("prefix"), result)
+
+	result, err = p.ParseToBinary([]byte("prefix"), []byte(`{"id": `))
+	assert.Error(t, err)
+	assert.Equal(t, []byte("prefix"), result)
+}
+
+func TestParseToBinaryEmptyRecord(t *testing.T) {
+	// the nested records have no bytes in the binary encoding
+	schema := `
+	{
+		"name": "Spell",
+		"type": "record",
+		"fields": [
+			{
+				"name": "effect",
+				"type": "record",
+				"fields": [
+					{"name": "nothing", "type": "null"},
+					{
+						"name": "inner",
+						"type": "record",
+						"fields": [{"name": "nothing", "type": "null"}]
+					}
+				]
+			},
+			{"name": "name", "type": "string"}
+		]
+	}
+	`
+
+	codec, err := goavro.NewCodec(schema)
+	assert.NoError(t, err)
+
+	p, err := NewParser(schema)
+	assert.NoError(t, err)
+
+	record := `{"effect": {"nothing": null, "inner": {"nothing": null}}, "name": "lumos"}`
+
+	native, err := p.Parse([]byte(record))
+	assert.NoError(t, err)
+
+	expected, err := codec.BinaryFromNative(nil, native)
+	assert.NoError(t, err)
+
+	result, err := p.ParseToBinary(nil, []byte(record))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
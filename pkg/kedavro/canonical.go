@@ -0,0 +1,291 @@
+package kedavro
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ouzi-dev/avro-kedavro/pkg/types"
+)
+
+// rabinEmpty is the fingerprint of an empty string, used to build the table of
+// the CRC-64-AVRO fingerprint
+const rabinEmpty = uint64(0xc15d213aa4d7a795)
+
+var rabinTable = getRabinTable()
+
+var primitiveTypes = map[string]bool{
+	types.NilType:    true,
+	types.BoolType:   true,
+	types.IntType:    true,
+	types.LongType:   true,
+	types.FloatType:  true,
+	types.DoubleType: true,
+	types.BytesType:  true,
+	types.StringType: true,
+}
+
+// complexTypes are the types that can be declared in the field itself,
+// instead of in the type of the field
+var complexTypes = map[string]bool{
+	types.RecordType: true,
+	"error":          true,
+	"enum":           true,
+	"fixed":          true,
+	"array":          true,
+	types.MapType:    true,
+}
+
+func getRabinTable() [256]uint64 {
+	table := [256]uint64{}
+	for i := range table {
+		fp := uint64(i)
+		for j := 0; j < 8; j++ {
+			fp = (fp >> 1) ^ (rabinEmpty & -(fp & 1))
+		}
+		table[i] = fp
+	}
+	return table
+}
+
+func getRabinFingerprint(data []byte) uint64 {
+	fp := rabinEmpty
+	for _, b := range data {
+		fp = (fp >> 8) ^ rabinTable[byte(fp)^b]
+	}
+	return fp
+}
+
+// CanonicalForm returns the parsing canonical form of a schema, as defined in
+// the avro specification: without whitespace, attributes not needed to parse
+// the data like doc, aliases or default values and with full names
+func CanonicalForm(schemaString string) (string, error) {
+	var schema interface{}
+	if err := json.Unmarshal([]byte(schemaString), &schema); err != nil {
+		return "", fmt.Errorf("unmarshall schema failed: %v", err)
+	}
+
+	buf, err := appendCanonicalSchema(nil, schema, "")
+	if err != nil {
+		return "", err
+	}
+
+	return string(buf), nil
+}
+
+// FingerprintCRC64 returns the CRC-64-AVRO (Rabin) fingerprint of the parsing
+// canonical form of a schema, the same used by the single object encoding
+func FingerprintCRC64(schemaString string) (uint64, error) {
+	canonicalForm, err := CanonicalForm(schemaString)
+	if err != nil {
+		return 0, err
+	}
+
+	return getRabinFingerprint([]byte(canonicalForm)), nil
+}
+
+// FingerprintMD5 returns the MD5 fingerprint of the parsing canonical form of a
+// schema
+func FingerprintMD5(schemaString string) ([md5.Size]byte, error) {
+	canonicalForm, err := CanonicalForm(schemaString)
+	if err != nil {
+		return [md5.Size]byte{}, err
+	}
+
+	return md5.Sum([]byte(canonicalForm)), nil
+}
+
+// FingerprintSHA256 returns the SHA-256 fingerprint of the parsing canonical
+// form of a schema
+func FingerprintSHA256(schemaString string) ([sha256.Size]byte, error) {
+	canonicalForm, err := CanonicalForm(schemaString)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+
+	return sha256.Sum256([]byte(canonicalForm)), nil
+}
+
+func appendCanonicalSchema(buf []byte, schema interface{}, namespace string) ([]byte, error) {
+	switch v := schema.(type) {
+	case string:
+		if primitiveTypes[v] {
+			return appendCanonicalString(buf, v), nil
+		}
+		return appendCanonicalString(buf, getFullName(v, namespace)), nil
+	case []interface{}:
+		buf = append(buf, '[')
+		for i, item := range v {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			var err error
+			if buf, err = appendCanonicalSchema(buf, item, namespace); err != nil {
+				return nil, err
+			}
+		}
+		return append(buf, ']'), nil
+	case map[string]interface{}:
+		return appendCanonicalObject(buf, v, namespace)
+	default:
+		return nil, fmt.Errorf("invalid schema \"%v\"", schema)
+	}
+}
+
+// nolint gocyclo
+func appendCanonicalObject(buf []byte, schema map[string]interface{}, namespace string) ([]byte, error) {
+	typeName, ok := schema["type"].(string)
+	if !ok {
+		// the type is a schema itself, the rest of the attributes are stripped
+		typeValue, found := schema["type"]
+		if !found {
+			return nil, fmt.Errorf("missing type in schema \"%v\"", schema)
+		}
+		return appendCanonicalSchema(buf, typeValue, namespace)
+	}
+
+	if !complexTypes[typeName] {
+		return appendCanonicalSchema(buf, typeName, namespace)
+	}
+
+	var err error
+	buf = append(buf, '{')
+
+	switch typeName {
+	case types.RecordType, "error", "enum", "fixed":
+		name, ok := schema["name"].(string)
+		if !ok {
+			return nil, fmt.Errorf("missing name in schema of type \"%s\"", typeName)
+		}
+		if ns, ok := schema["namespace"].(string); ok && !strings.Contains(name, ".") {
+			namespace = ns
+		}
+		name = getFullName(name, namespace)
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			namespace = name[:i]
+		} else {
+			namespace = ""
+		}
+		buf = append(buf, `"name":`...)
+		buf = appendCanonicalString(buf, name)
+		buf = append(buf, ',')
+	}
+
+	buf = append(buf, `"type":`...)
+	buf = appendCanonicalString(buf, typeName)
+
+	switch typeName {
+	case types.RecordType, "error":
+		fields, ok := schema["fields"].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("missing fields in schema of record \"%v\"", schema["name"])
+		}
+		buf = append(buf, `,"fields":[`...)
+		for i, f := range fields {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			if buf, err = appendCanonicalField(buf, f, namespace); err != nil {
+				return nil, err
+			}
+		}
+		buf = append(buf, ']')
+	case "enum":
+		symbols, ok := schema["symbols"].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("missing symbols in schema of enum \"%v\"", schema["name"])
+		}
+		buf = append(buf, `,"symbols":[`...)
+		for i, s := range symbols {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendCanonicalString(buf, fmt.Sprintf("%v", s))
+		}
+		buf = append(buf, ']')
+	case "array":
+		buf = append(buf, `,"items":`...)
+		if buf, err = appendCanonicalSchema(buf, schema["items"], namespace); err != nil {
+			return nil, err
+		}
+	case types.MapType:
+		buf = append(buf, `,"values":`...)
+		if buf, err = appendCanonicalSchema(buf, schema["values"], namespace); err != nil {
+			return nil, err
+		}
+	case "fixed":
+		size, err := getCanonicalSize(schema["size"])
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, `,"size":`...)
+		buf = strconv.AppendInt(buf, size, 10)
+	}
+
+	return append(buf, '}'), nil
+}
+
+func appendCanonicalField(buf []byte, field interface{}, namespace string) ([]byte, error) {
+	f, ok := field.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid field \"%v\"", field)
+	}
+
+	name, ok := f["name"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing name in field \"%v\"", field)
+	}
+
+	buf = append(buf, `{"name":`...)
+	buf = appendCanonicalString(buf, name)
+	buf = append(buf, `,"type":`...)
+
+	var err error
+	if typeName, ok := f["type"].(string); ok && complexTypes[typeName] {
+		// complex types declared in the field itself, like {"name": "address", "type": "record", "fields": [...]}
+		buf, err = appendCanonicalObject(buf, f, namespace)
+	} else {
+		buf, err = appendCanonicalSchema(buf, f["type"], namespace)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return append(buf, '}'), nil
+}
+
+func appendCanonicalString(buf []byte, value string) []byte {
+	encoded := &bytes.Buffer{}
+	encoder := json.NewEncoder(encoded)
+	encoder.SetEscapeHTML(false)
+	// encoding a string never fails
+	_ = encoder.Encode(value)
+
+	return append(buf, bytes.TrimSuffix(encoded.Bytes(), []byte("\n"))...)
+}
+
+func getCanonicalSize(size interface{}) (int64, error) {
+	switch v := size.(type) {
+	case float64:
+		return int64(v), nil
+	case string:
+		s, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid size \"%s\" in schema of fixed", v)
+		}
+		return s, nil
+	default:
+		return 0, fmt.Errorf("invalid size \"%v\" in schema of fixed", size)
+	}
+}
+
+func getFullName(name string, namespace string) string {
+	if namespace == "" || strings.Contains(name, ".") {
+		return name
+	}
+	return namespace + "." + name
+}
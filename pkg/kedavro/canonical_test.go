@@ -0,0 +1,104 @@
+package kedavro
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCanonicalForm(t *testing.T) {
+	tests := []struct {
+		schema   string
+		expected string
+	}{
+		{schema: `"int"`, expected: `"int"`},
+		{schema: `{"type": "string"}`, expected: `"string"`},
+		{schema: `{"type": "long", "logicalType": "timestamp-millis"}`, expected: `"long"`},
+		{schema: `["null", {"type": "string"}]`, expected: `["null","string"]`},
+		{
+			schema:   `{"type": "record", "name": "Wizard", "namespace": "com.avro.kedavro", "doc": "a wizard", "fields": [{"name": "name", "type": "string", "default": "harry", "aliases": ["firstName"]}]}`,
+			expected: `{"name":"com.avro.kedavro.Wizard","type":"record","fields":[{"name":"name","type":"string"}]}`,
+		},
+		{
+			schema:   `{"name": "Wizard", "type": "record", "fields": [{"name": "address", "type": "record", "default": {"street": "privet drive"}, "fields": [{"name": "street", "type": "string"}]}]}`,
+			expected: `{"name":"Wizard","type":"record","fields":[{"name":"address","type":{"name":"address","type":"record","fields":[{"name":"street","type":"string"}]}}]}`,
+		},
+		{
+			schema:   `{"name": "kedavro.Wizard", "type": "record", "fields": [{"name": "pet", "type": {"name": "Pet", "type": "record", "fields": [{"name": "name", "type": "string"}]}}, {"name": "other", "type": ["null", "Pet"]}]}`,
+			expected: `{"name":"kedavro.Wizard","type":"record","fields":[{"name":"pet","type":{"name":"kedavro.Pet","type":"record","fields":[{"name":"name","type":"string"}]}},{"name":"other","type":["null","kedavro.Pet"]}]}`,
+		},
+		{
+			schema:   `{"name": "Wizard", "type": "record", "fields": [{"name": "extra", "type": {"type": "map", "values": "string"}, "kedavro.catchAll": true}]}`,
+			expected: `{"name":"Wizard","type":"record","fields":[{"name":"extra","type":{"type":"map","values":"string"}}]}`,
+		},
+		{
+			schema:   `{"type": "array", "items": {"type": "enum", "name": "House", "namespace": "hogwarts", "symbols": ["GRYFFINDOR", "SLYTHERIN"]}}`,
+			expected: `{"type":"array","items":{"name":"hogwarts.House","type":"enum","symbols":["GRYFFINDOR","SLYTHERIN"]}}`,
+		},
+		{schema: `{"type": "fixed", "name": "Md5", "size": 16}`, expected: `{"name":"Md5","type":"fixed","size":16}`},
+	}
+
+	for _, test := range tests {
+		result, err := CanonicalForm(test.schema)
+		assert.NoError(t, err, test.schema)
+		assert.Equal(t, test.expected, result, test.schema)
+	}
+
+	_, err := CanonicalForm(`{"type": "record", "fields": []}`)
+	assert.EqualError(t, err, `missing name in schema of type "record"`)
+
+	_, err = CanonicalForm(`{`)
+	assert.EqualError(t, err, "unmarshall schema failed: unexpected end of JSON input")
+}
+
+func TestGetRabinFingerprint(t *testing.T) {
+	schemas := []string{
+		`"null"`,
+		`"int"`,
+		`["null", "string"]`,
+		`{"type": "record", "name": "Wizard", "namespace": "com.avro.kedavro", "fields": [{"name": "name", "type": ["null", "string"], "default": null}, {"name": "id", "type": "long"}]}`,
+		writerSchema,
+	}
+
+	for _, v := range schemas {
+		codec, err := goavro.NewCodec(v)
+		assert.NoError(t, err, v)
+
+		canonicalForm, err := CanonicalForm(v)
+		assert.NoError(t, err, v)
+		assert.Equal(t, codec.Rabin, getRabinFingerprint([]byte(canonicalForm)), v)
+	}
+}
+
+func TestFingerprints(t *testing.T) {
+	schemas := []string{
+		`{"name":"Wizard","type":"record","fields":[{"name":"name","type":"string"}]}`,
+		`{"type": "record", "name": "Wizard", "doc": "the same schema", "fields": [{"name": "name", "type": {"type": "string"}, "default": "harry"}]}`,
+	}
+
+	for _, v := range schemas {
+		codec, err := goavro.NewCodec(v)
+		assert.NoError(t, err, v)
+
+		crc64, err := FingerprintCRC64(v)
+		assert.NoError(t, err, v)
+		assert.Equal(t, codec.Rabin, crc64, v)
+
+		md5, err := FingerprintMD5(v)
+		assert.NoError(t, err, v)
+		assert.Equal(t, "9521ffedff5bb978aa934afa1bfca5d4", hex.EncodeToString(md5[:]), v)
+
+		sha256, err := FingerprintSHA256(v)
+		assert.NoError(t, err, v)
+		assert.Equal(t, "08d4618f05449699d0f4f8d83b8d76c71de9b357c247a34718aded7ea64ac8d8", hex.EncodeToString(sha256[:]), v)
+	}
+
+	_, err := FingerprintCRC64(`{`)
+	assert.Error(t, err)
+	_, err = FingerprintMD5(`{`)
+	assert.Error(t, err)
+	_, err = FingerprintSHA256(`{`)
+	assert.Error(t, err)
+}
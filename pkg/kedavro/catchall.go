@@ -0,0 +1,89 @@
+package kedavro
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ouzi-dev/avro-kedavro/pkg/types"
+)
+
+// catchAllAttribute is the attribute to mark the field that gets the keys of
+// the JSON record not used by any other field
+const catchAllAttribute = "kedavro.catchAll"
+
+func getCatchAll(fieldMap map[string]interface{}) (bool, error) {
+	catchAllValue, ok := fieldMap[catchAllAttribute]
+	if !ok {
+		return false, nil
+	}
+
+	catchAll, ok := catchAllValue.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s has to be a boolean, but it's current value is: %v", catchAllAttribute, catchAllValue)
+	}
+
+	return catchAll, nil
+}
+
+// validateCatchAllField checks a field of type map, we only support maps of
+// strings as catch-all fields
+func validateCatchAllField(name string, fieldMap map[string]interface{}, isCatchAll bool) error {
+	if !isCatchAll {
+		return fmt.Errorf("type \"%s\" is only supported for catch-all fields, field name \"%s\"", types.MapType, name)
+	}
+	if fieldMap["values"] != types.StringType {
+		return fmt.Errorf("catch-all field \"%s\" has to be of type map of strings, values: %v", name, fieldMap["values"])
+	}
+	if _, ok := fieldMap["default"]; ok {
+		return fmt.Errorf("catch-all field \"%s\" can't have a default value", name)
+	}
+	if _, ok := fieldMap[sourceAttribute]; ok {
+		return fmt.Errorf("catch-all field \"%s\" can't have a source path", name)
+	}
+
+	return nil
+}
+
+// getCatchAllField returns the catch-all field of a record, if it has one
+func getCatchAllField(name string, fields []*Field) (*Field, error) {
+	var catchAll *Field
+	for _, v := range fields {
+		if !v.CatchAll {
+			continue
+		}
+		if catchAll != nil {
+			return nil, fmt.Errorf("record \"%s\" has more than one catch-all field: \"%s\" and \"%s\"", name, catchAll.Name, v.Name)
+		}
+		catchAll = v
+	}
+
+	return catchAll, nil
+}
+
+// getCatchAllValue returns the keys of the JSON record not used by any field,
+// with their values serialized as JSON
+func getCatchAllValue(record map[string]interface{}, used usedKeys) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	for key, value := range record {
+		if _, ok := used[key]; ok {
+			continue
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("serializing value of key \"%s\" error: %v", key, err)
+		}
+		result[key] = string(b)
+	}
+
+	return result, nil
+}
+
+func parseCatchAllField(field *Field, record map[string]interface{}, state *parseState) (interface{}, error) {
+	// the record parser already put the unknown keys here
+	value, ok := record[field.Name].(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}, nil
+	}
+
+	return value, nil
+}
@@ -0,0 +1,190 @@
+package kedavro
+
+import (
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+const catchAllSchema = `
+{
+	"name": "Wizard",
+	"type": "record",
+	"fields": [
+		{
+			"name": "name",
+			"type": "string"
+		},
+		{
+			"name": "house",
+			"type": "string",
+			"aliases": ["school_house"]
+		},
+		{
+			"name": "wand",
+			"type": "record",
+			"fields": [
+				{
+					"name": "wood",
+					"type": "string"
+				},
+				{
+					"name": "extra",
+					"type": "map",
+					"values": "string",
+					"kedavro.catchAll": true
+				}
+			]
+		},
+		{
+			"name": "extra",
+			"type": {
+				"type": "map",
+				"values": "string"
+			},
+			"kedavro.catchAll": true
+		}
+	]
+}
+`
+
+func TestCatchAllField(t *testing.T) {
+	p, err := NewParser(catchAllSchema, WithStrictFields())
+	assert.NoError(t, err)
+
+	record := `
+	{
+		"name": "harry",
+		"school_house": "gryffindor",
+		"age": 17,
+		"pet": {"name": "hedwig", "kind": "owl"},
+		"friends": ["ron", "hermione"],
+		"nickname": "the boy who lived",
+		"wand": {"wood": "holly", "core": "phoenix feather"}
+	}
+	`
+
+	result, err := p.Parse([]byte(record))
+	assert.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"name":  "harry",
+		"house": "gryffindor",
+		"wand": map[string]interface{}{
+			"wood": "holly",
+			"extra": map[string]interface{}{
+				"core": `"phoenix feather"`,
+			},
+		},
+		"extra": map[string]interface{}{
+			"age":      "17",
+			"pet":      `{"kind":"owl","name":"hedwig"}`,
+			"friends":  `["ron","hermione"]`,
+			"nickname": `"the boy who lived"`,
+		},
+	}
+	assert.Equal(t, expected, result)
+
+	codec, err := goavro.NewCodec(catchAllSchema)
+	assert.NoError(t, err)
+
+	_, err = codec.BinaryFromNative(nil, result)
+	assert.NoError(t, err)
+
+	// without unknown keys the catch-all field is empty
+	result, err = p.Parse([]byte(`{"name": "harry", "house": "gryffindor", "wand": {"wood": "holly"}}`))
+	assert.NoError(t, err)
+
+	expected = map[string]interface{}{
+		"name":  "harry",
+		"house": "gryffindor",
+		"wand": map[string]interface{}{
+			"wood":  "holly",
+			"extra": map[string]interface{}{},
+		},
+		"extra": map[string]interface{}{},
+	}
+	assert.Equal(t, expected, result)
+}
+
+func TestCatchAllFieldInRecordDefaults(t *testing.T) {
+	schema := `
+	{
+		"name": "Wizard",
+		"type": "record",
+		"fields": [
+			{
+				"name": "wand",
+				"type": "record",
+				"default": {"wood": "holly"},
+				"fields": [
+					{
+						"name": "wood",
+						"type": "string"
+					},
+					{
+						"name": "extra",
+						"type": {"type": "map", "values": "string"},
+						"kedavro.catchAll": true
+					}
+				]
+			},
+			{
+				"name": "broom",
+				"type": "record",
+				"fields": [
+					{
+						"name": "model",
+						"type": "string",
+						"default": "nimbus 2000"
+					},
+					{
+						"name": "extra",
+						"type": {"type": "map", "values": "string"},
+						"kedavro.catchAll": true
+					}
+				]
+			}
+		]
+	}
+	`
+
+	p, err := NewParser(schema, WithRecordDefaultFromFields())
+	assert.NoError(t, err)
+
+	result, err := p.Parse([]byte(`{}`))
+	assert.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"wand": map[string]interface{}{
+			"wood":  "holly",
+			"extra": map[string]interface{}{},
+		},
+		"broom": map[string]interface{}{
+			"model": "nimbus 2000",
+			"extra": map[string]interface{}{},
+		},
+	}
+	assert.Equal(t, expected, result)
+}
+
+func TestInvalidCatchAllFields(t *testing.T) {
+	tests := []string{
+		`{"name": "extra", "type": {"type": "map", "values": "string"}}`,
+		`{"name": "extra", "type": {"type": "map", "values": "long"}, "kedavro.catchAll": true}`,
+		`{"name": "extra", "type": "string", "kedavro.catchAll": true}`,
+		`{"name": "extra", "type": ["null", "string"], "kedavro.catchAll": true}`,
+		`{"name": "extra", "type": {"type": "map", "values": "string"}, "kedavro.catchAll": "true"}`,
+		`{"name": "extra", "type": {"type": "map", "values": "string"}, "kedavro.catchAll": true, "default": {}}`,
+		`{"name": "extra", "type": {"type": "map", "values": "string"}, "kedavro.catchAll": true, "kedavro.source": "$.extra"}`,
+		`{"name": "extra", "type": {"type": "map", "values": "string"}, "kedavro.catchAll": true},
+		{"name": "other", "type": {"type": "map", "values": "string"}, "kedavro.catchAll": true}`,
+	}
+
+	for _, v := range tests {
+		schema := `{"name": "Test", "type": "record", "fields": [` + v + `]}`
+		_, err := NewParser(schema)
+		assert.Error(t, err, v)
+	}
+}
@@ -0,0 +1,43 @@
+package kedavro
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// confluentMagicByte is the first byte of a message in the confluent wire
+// format, followed by the schema id as a 4 bytes big endian integer
+const confluentMagicByte = 0
+
+func (p *parser) ParseToConfluent(buf []byte, record []byte) ([]byte, error) {
+	jsonRecord, err := unmarshalRecord(record)
+	if err != nil {
+		return buf, err
+	}
+
+	return p.ParseMapToConfluent(buf, jsonRecord)
+}
+
+func (p *parser) ParseMapToConfluent(buf []byte, record map[string]interface{}) ([]byte, error) {
+	if !p.schema.Opts.IsSchemaID {
+		return buf, fmt.Errorf("confluent wire format requires a schema id")
+	}
+
+	start := len(buf)
+	buf = appendConfluentHeader(buf, p.schema.Opts.SchemaID)
+
+	result, err := p.ParseMapToBinary(buf, record)
+	if err != nil {
+		return result[:start], err
+	}
+
+	return result, nil
+}
+
+func appendConfluentHeader(buf []byte, id uint32) []byte {
+	header := make([]byte, 5)
+	header[0] = confluentMagicByte
+	binary.BigEndian.PutUint32(header[1:], id)
+
+	return append(buf, header...)
+}
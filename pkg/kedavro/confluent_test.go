@@ -0,0 +1,47 @@
+package kedavro
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseToConfluent(t *testing.T) {
+	registry := NewMemorySchemaRegistry()
+	_, err := registry.Register("wizards-value", binarySchema)
+	assert.NoError(t, err)
+	id, err := registry.Register("wizards-value", writerSchema)
+	assert.NoError(t, err)
+
+	p, err := NewParser(writerSchema, WithSchemaID(id))
+	assert.NoError(t, err)
+
+	for _, v := range writerRecords {
+		expected, err := p.ParseToBinary(nil, []byte(v))
+		assert.NoError(t, err, v)
+
+		result, err := p.ParseToConfluent([]byte("prefix"), []byte(v))
+		assert.NoError(t, err, v)
+		assert.Equal(t, append([]byte("prefix\x00\x00\x00\x00\x02"), expected...), result, v)
+	}
+
+	result, err := p.ParseToConfluent([]byte("prefix"), []byte(`{"name": "hedwig", "house": null, "born": "never"}`))
+	assert.Error(t, err)
+	assert.Equal(t, []byte("prefix"), result)
+
+	result, err = p.ParseToConfluent([]byte("prefix"), []byte(`{`))
+	assert.Error(t, err)
+	assert.Equal(t, []byte("prefix"), result)
+
+	p, err = NewParser(writerSchema, WithSchemaID(0x01020304))
+	assert.NoError(t, err)
+	result, err = p.ParseToConfluent(nil, []byte(writerRecords[0]))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 1, 2, 3, 4}, result[:5])
+
+	p, err = NewParser(writerSchema)
+	assert.NoError(t, err)
+	result, err = p.ParseToConfluent([]byte("prefix"), []byte(writerRecords[0]))
+	assert.EqualError(t, err, "confluent wire format requires a schema id")
+	assert.Equal(t, []byte("prefix"), result)
+}
@@ -0,0 +1,290 @@
+package kedavro
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/ouzi-dev/avro-kedavro/pkg/types"
+)
+
+// Converter converts avro native data, as returned by goavro or Parse, back
+// to plain JSON
+type Converter interface {
+	Convert(native interface{}) ([]byte, error)
+	ConvertToMap(native interface{}) (map[string]interface{}, error)
+}
+
+type converter struct {
+	schema  *Field
+	options converterOptions
+}
+
+type converterOptions struct {
+	isTimestampToSeconds bool
+	isFormatTimestamp    bool
+	timestampFormat      string
+	isNumberToString     bool
+	isBoolToString       bool
+}
+
+// ConverterOption reconfigure the converter creation.
+type ConverterOption func(*converterOptions)
+
+func WithTimestampToSeconds() ConverterOption {
+	return func(o *converterOptions) { o.isTimestampToSeconds = true }
+}
+
+func WithTimestampFormat(format string) ConverterOption {
+	return func(o *converterOptions) {
+		o.isFormatTimestamp = true
+		o.timestampFormat = format
+	}
+}
+
+func WithNumberToString() ConverterOption {
+	return func(o *converterOptions) { o.isNumberToString = true }
+}
+
+func WithBoolToString() ConverterOption {
+	return func(o *converterOptions) { o.isBoolToString = true }
+}
+
+func NewConverter(schemaString string, opts ...ConverterOption) (Converter, error) {
+	s := map[string]interface{}{}
+
+	if err := json.Unmarshal([]byte(schemaString), &s); err != nil {
+		return nil, fmt.Errorf("unmarshall schema failed: %v", err)
+	}
+
+	options := converterOptions{}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.isTimestampToSeconds && options.isFormatTimestamp {
+		return nil, fmt.Errorf("timestamps can't be converted to seconds and formatted at the same time")
+	}
+
+	rootField, err := ParseSchemaField(s, types.Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	if rootField.Type != types.Primitive || rootField.TypeValue.(string) != "record" {
+		return nil, fmt.Errorf("schema root field must be of type record")
+	}
+
+	return &converter{
+		schema:  rootField,
+		options: options,
+	}, nil
+}
+
+func (c *converter) Convert(native interface{}) ([]byte, error) {
+	record, err := c.ConvertToMap(native)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("marshall record failed: %v", err)
+	}
+
+	return result, nil
+}
+
+func (c *converter) ConvertToMap(native interface{}) (map[string]interface{}, error) {
+	record, ok := native.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot convert value \"%v\" of field \"%s\" as \"record\"", native, c.schema.Name)
+	}
+
+	return c.convertRecord(c.schema, record)
+}
+
+func (c *converter) convertValue(field *Field, value interface{}) (interface{}, error) {
+	switch field.Type {
+	case types.Union:
+		return c.convertUnion(field, value)
+	case types.Primitive:
+		return c.convertPrimitive(field, field.TypeValue.(string), value)
+	default:
+		return nil, fmt.Errorf("unknown field type in field %s", field.Name)
+	}
+}
+
+func (c *converter) convertUnion(field *Field, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	branch := getUnionValueType(field)
+	union, ok := value.(map[string]interface{})
+	if !ok || len(union) != 1 {
+		return nil, fmt.Errorf("cannot convert value \"%v\" of field \"%s\" as \"union\"", value, field.Name)
+	}
+
+	// unions are unwrapped, the key is the type of the value so it's not needed
+	for _, v := range union {
+		return c.convertValue(getUnionBranchField(field, branch), v)
+	}
+
+	return nil, nil
+}
+
+// nolint gocyclo
+func (c *converter) convertPrimitive(field *Field, typeName string, value interface{}) (interface{}, error) {
+	var ok bool
+	switch typeName {
+	case types.NilType:
+		if value == nil {
+			return nil, nil
+		}
+	case types.BoolType:
+		var v bool
+		if v, ok = value.(bool); ok {
+			if c.options.isBoolToString {
+				return strconv.FormatBool(v), nil
+			}
+			return v, nil
+		}
+	case types.IntType:
+		var v int32
+		if v, ok = value.(int32); ok {
+			return c.convertInteger(int64(v)), nil
+		}
+	case types.LongType:
+		if field.LogicalType == types.TimestampMillis || field.LogicalType == types.TimestampMicros {
+			// goavro returns an epoch in the unit of the timestamp when the
+			// logical type is in the field, like in unions
+			switch v := value.(type) {
+			case time.Time:
+				return c.convertTimestamp(field, v), nil
+			case int64:
+				return c.convertTimestamp(field, getLongAsTimestamp(field, v)), nil
+			}
+			break
+		}
+		var v int64
+		if v, ok = value.(int64); ok {
+			return c.convertInteger(v), nil
+		}
+	case types.FloatType:
+		var v float32
+		if v, ok = value.(float32); ok {
+			return c.convertFloat(float64(v), 32), nil
+		}
+	case types.DoubleType:
+		var v float64
+		if v, ok = value.(float64); ok {
+			return c.convertFloat(v, 64), nil
+		}
+	case types.StringType:
+		var v string
+		if v, ok = value.(string); ok {
+			return v, nil
+		}
+	case types.BytesType:
+		var v []byte
+		if v, ok = value.([]byte); ok {
+			return base64.StdEncoding.EncodeToString(v), nil
+		}
+	case types.RecordType:
+		var v map[string]interface{}
+		if v, ok = value.(map[string]interface{}); ok {
+			return c.convertRecord(field, v)
+		}
+	}
+
+	return nil, fmt.Errorf("cannot convert value \"%v\" of field \"%s\" as \"%s\"", value, field.Name, typeName)
+}
+
+func (c *converter) convertRecord(field *Field, record map[string]interface{}) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+
+	for _, v := range field.Fields {
+		if v.CatchAll {
+			continue
+		}
+		value, err := c.convertValue(v, record[v.Name])
+		if err != nil {
+			return nil, err
+		}
+		result[v.Name] = value
+	}
+
+	if field.catchAllField == nil {
+		return result, nil
+	}
+
+	// the keys of the catch-all field are merged back into the record, the
+	// fields of the record win if the same key is found
+	values, ok := record[field.catchAllField.Name].(map[string]interface{})
+	if !ok && record[field.catchAllField.Name] != nil {
+		return nil, fmt.Errorf("cannot convert value \"%v\" of field \"%s\" as \"map\"", record[field.catchAllField.Name], field.catchAllField.Name)
+	}
+
+	for k, v := range values {
+		if _, found := result[k]; found {
+			continue
+		}
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot convert value \"%v\" of key \"%s\" in field \"%s\" as \"string\"", v, k, field.catchAllField.Name)
+		}
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(s), &decoded); err != nil {
+			return nil, fmt.Errorf("cannot convert value \"%v\" of key \"%s\" in field \"%s\": %v", v, k, field.catchAllField.Name, err)
+		}
+		result[k] = decoded
+	}
+
+	return result, nil
+}
+
+func (c *converter) convertTimestamp(field *Field, t time.Time) interface{} {
+	switch {
+	case c.options.isFormatTimestamp:
+		return t.UTC().Format(c.options.timestampFormat)
+	case c.options.isTimestampToSeconds:
+		return c.convertInteger(t.Unix())
+	default:
+		return c.convertInteger(getTimestampAsLong(field, t))
+	}
+}
+
+// getLongAsTimestamp is the inverse of getTimestampAsLong
+func getLongAsTimestamp(field *Field, value int64) time.Time {
+	if field.LogicalType == types.TimestampMillis {
+		return time.Unix(value/1e3, value%1e3*int64(time.Millisecond))
+	}
+	return time.Unix(value/1e6, value%1e6*int64(time.Microsecond))
+}
+
+func (c *converter) convertInteger(value int64) interface{} {
+	if c.options.isNumberToString {
+		return strconv.FormatInt(value, 10)
+	}
+	return value
+}
+
+// convertFloat converts NaN and infinity to null, JSON doesn't support them
+func (c *converter) convertFloat(value float64, bitSize int) interface{} {
+	switch {
+	case math.IsNaN(value) || math.IsInf(value, 0):
+		return nil
+	case c.options.isNumberToString:
+		return strconv.FormatFloat(value, 'g', -1, bitSize)
+	case bitSize == 32:
+		// keeps float32 so it is marshalled with its shortest representation
+		return float32(value)
+	default:
+		return value
+	}
+}
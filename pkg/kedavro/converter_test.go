@@ -0,0 +1,182 @@
+package kedavro
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+const converterSchema = `
+{
+	"name": "Wizard",
+	"type": "record",
+	"fields": [
+		{"name": "name", "type": "string"},
+		{"name": "alive", "type": "boolean"},
+		{"name": "wand", "type": "bytes"},
+		{"name": "power", "type": "float"},
+		{"name": "age", "type": "int"},
+		{"name": "born", "type": "long", "logicalType": "timestamp-millis"},
+		{"name": "house", "type": ["null", "string"]},
+		{
+			"name": "school",
+			"type": "record",
+			"fields": [
+				{"name": "name", "type": "string"},
+				{"name": "year", "type": ["null", "int"]}
+			]
+		},
+		{
+			"name": "extra",
+			"type": {"type": "map", "values": "string"},
+			"kedavro.catchAll": true
+		}
+	]
+}
+`
+
+func TestConverter(t *testing.T) {
+	record := `{"name": "harry", "alive": true, "wand": "holly", "power": 1.1, "age": 17, "born": 1571128870123, "house": "gryffindor", "school": {"name": "hogwarts", "year": 7}, "owl": {"name": "hedwig"}}`
+
+	tests := []struct {
+		options  []ConverterOption
+		expected string
+	}{
+		{
+			expected: `{"name": "harry", "alive": true, "wand": "aG9sbHk=", "power": 1.1, "age": 17, "born": 1571128870123, "house": "gryffindor", "school": {"name": "hogwarts", "year": 7}, "owl": {"name": "hedwig"}}`,
+		},
+		{
+			options:  []ConverterOption{WithTimestampToSeconds()},
+			expected: `{"name": "harry", "alive": true, "wand": "aG9sbHk=", "power": 1.1, "age": 17, "born": 1571128870, "house": "gryffindor", "school": {"name": "hogwarts", "year": 7}, "owl": {"name": "hedwig"}}`,
+		},
+		{
+			options:  []ConverterOption{WithTimestampFormat(time.RFC3339Nano)},
+			expected: `{"name": "harry", "alive": true, "wand": "aG9sbHk=", "power": 1.1, "age": 17, "born": "2019-10-15T08:41:10.123Z", "house": "gryffindor", "school": {"name": "hogwarts", "year": 7}, "owl": {"name": "hedwig"}}`,
+		},
+		{
+			options:  []ConverterOption{WithNumberToString(), WithBoolToString()},
+			expected: `{"name": "harry", "alive": "true", "wand": "aG9sbHk=", "power": "1.1", "age": "17", "born": "1571128870123", "house": "gryffindor", "school": {"name": "hogwarts", "year": "7"}, "owl": {"name": "hedwig"}}`,
+		},
+	}
+
+	codec, err := goavro.NewCodec(converterSchema)
+	assert.NoError(t, err)
+
+	p, err := NewParser(converterSchema)
+	assert.NoError(t, err)
+
+	encoded, err := p.ParseToBinary(nil, []byte(record))
+	assert.NoError(t, err)
+
+	native, _, err := codec.NativeFromBinary(encoded)
+	assert.NoError(t, err)
+
+	for _, test := range tests {
+		c, err := NewConverter(converterSchema, test.options...)
+		assert.NoError(t, err)
+
+		result, err := c.Convert(native)
+		assert.NoError(t, err)
+		assert.JSONEq(t, test.expected, string(result))
+	}
+}
+
+func TestConverterUnionTimestamp(t *testing.T) {
+	schema := `
+	{
+		"name": "Wizard",
+		"type": "record",
+		"fields": [
+			{"name": "born", "type": ["null", "long"], "logicalType": "timestamp-millis"},
+			{"name": "sorted", "type": ["null", "long"], "logicalType": "timestamp-micros"}
+		]
+	}
+	`
+
+	codec, err := goavro.NewCodec(schema)
+	assert.NoError(t, err)
+
+	encoded, err := codec.BinaryFromNative(nil, map[string]interface{}{
+		"born":   goavro.Union("long", int64(2000)),
+		"sorted": goavro.Union("long", int64(-2500000)),
+	})
+	assert.NoError(t, err)
+
+	// goavro only knows the logical type of the union branch, so the timestamps are epochs
+	native, _, err := codec.NativeFromBinary(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"long": int64(2000)}, native.(map[string]interface{})["born"])
+
+	tests := []struct {
+		options  []ConverterOption
+		expected string
+	}{
+		{
+			expected: `{"born": 2000, "sorted": -2500000}`,
+		},
+		{
+			options:  []ConverterOption{WithTimestampToSeconds()},
+			expected: `{"born": 2, "sorted": -3}`,
+		},
+		{
+			options:  []ConverterOption{WithTimestampFormat(time.RFC3339Nano)},
+			expected: `{"born": "1970-01-01T00:00:02Z", "sorted": "1969-12-31T23:59:57.5Z"}`,
+		},
+	}
+
+	for _, test := range tests {
+		c, err := NewConverter(schema, test.options...)
+		assert.NoError(t, err)
+
+		result, err := c.Convert(native)
+		assert.NoError(t, err)
+		assert.JSONEq(t, test.expected, string(result))
+	}
+}
+
+func TestConverterParsedRecord(t *testing.T) {
+	p, err := NewParser(converterSchema)
+	assert.NoError(t, err)
+
+	native, err := p.Parse([]byte(`{"name": "dobby", "alive": false, "wand": "", "power": 0, "age": 0, "born": 0, "house": null, "school": {"name": "hogwarts", "year": null}}`))
+	assert.NoError(t, err)
+
+	c, err := NewConverter(converterSchema)
+	assert.NoError(t, err)
+
+	result, err := c.ConvertToMap(native)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"name":   "dobby",
+		"alive":  false,
+		"wand":   "",
+		"power":  float32(0),
+		"age":    int64(0),
+		"born":   int64(0),
+		"house":  nil,
+		"school": map[string]interface{}{"name": "hogwarts", "year": nil},
+	}, result)
+}
+
+func TestConverterErrors(t *testing.T) {
+	c, err := NewConverter(converterSchema)
+	assert.NoError(t, err)
+
+	_, err = c.Convert("harry")
+	assert.EqualError(t, err, `cannot convert value "harry" of field "Wizard" as "record"`)
+
+	_, err = c.Convert(map[string]interface{}{"name": 1})
+	assert.EqualError(t, err, `cannot convert value "1" of field "name" as "string"`)
+
+	_, err = c.Convert(map[string]interface{}{"name": "harry", "alive": true, "wand": []byte{}, "power": float32(math.NaN()), "age": int32(1), "born": time.Now(), "house": "gryffindor"})
+	assert.EqualError(t, err, `cannot convert value "gryffindor" of field "house" as "union"`)
+
+	_, err = NewConverter(converterSchema, WithTimestampToSeconds(), WithTimestampFormat(time.RFC3339))
+	assert.EqualError(t, err, "timestamps can't be converted to seconds and formatted at the same time")
+
+	_, err = NewConverter(`"string"`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,182 @@
+package kedavro
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/linkedin/goavro"
+	"github.com/ouzi-dev/avro-kedavro/pkg/types"
+)
+
+// setDefaultValues validates the default value of every field in the schema and
+// converts it to the native value goavro expects, so a bad default fails when
+// loading the schema instead of when parsing the first record without the field
+func setDefaultValues(field *Field, path string) error {
+	for _, v := range field.Fields {
+		fieldPath := path + "." + v.Name
+		// first the fields of the record, we need their defaults to build the default of the record
+		if err := setDefaultValues(v, fieldPath); err != nil {
+			return err
+		}
+
+		if v.HasDefault {
+			native, err := getNativeDefaultValue(v, v.DefaultValue, fieldPath)
+			if err != nil {
+				return err
+			}
+			v.NativeDefault = native
+			v.HasNativeDefault = true
+		} else if v.CatchAll {
+			// catch-all fields are empty when the record is missing
+			v.NativeDefault = map[string]interface{}{}
+			v.HasNativeDefault = true
+		} else if v.Opts.IsRecordDefaultFromFields && isRecordField(v) {
+			if native, ok := getRecordDefaultFromFields(v); ok {
+				v.NativeDefault = native
+				v.HasNativeDefault = true
+			}
+		}
+
+		if v.HasOnError && v.OnError == types.ErrorPolicyDefault && !v.HasNativeDefault {
+			return fmt.Errorf("error policy \"default\" requires a default value for field \"%s\"", fieldPath)
+		}
+	}
+
+	return nil
+}
+
+func getNativeDefaultValue(field *Field, value interface{}, path string) (interface{}, error) {
+	switch field.Type {
+	case types.Union:
+		firstType := field.TypeValue.([]interface{})[0].(string)
+		if firstType == types.NilType {
+			// we already validated this when parsing the schema... so value is null here
+			return nil, nil
+		}
+		// the default value of a union always has the type of the first type in the union
+		native, err := getNativeDefaultValue(getUnionBranchField(field, firstType), value, path)
+		if err != nil {
+			return nil, err
+		}
+		return goavro.Union(firstType, native), nil
+	case types.Primitive:
+		if isRecordField(field) {
+			return getRecordNativeDefaultValue(field, value, path)
+		}
+		return getPrimitiveNativeDefaultValue(field, field.TypeValue.(string), value, path)
+	default:
+		return nil, fmt.Errorf("unknown field type in field %s", path)
+	}
+}
+
+func getPrimitiveNativeDefaultValue(field *Field, typeName string, value interface{}, path string) (interface{}, error) {
+	if typeName == types.IntType || typeName == types.LongType {
+		if n, ok := value.(float64); ok && n != math.Trunc(n) {
+			return nil, fmt.Errorf("invalid default value for field \"%s\": value \"%v\" is not of type \"%s\"", path, value, typeName)
+		}
+	}
+	if n, ok := value.(float64); ok && typeName == types.IntType && (n < math.MinInt32 || n > math.MaxInt32) {
+		return nil, fmt.Errorf("invalid default value for field \"%s\": value \"%v\" overflows type \"int\"", path, value)
+	}
+
+	if s, ok := value.(string); ok && typeName == types.BytesType {
+		return getBytesDefaultValue(s, path)
+	}
+
+	parseValue, err := getParseValueFunction(typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	// defaults have to match the schema, so none of the coercion options apply to them
+	strictField := *field
+	strictField.Opts = types.Options{}
+
+	native, err := parseValue(&strictField, value, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default value for field \"%s\": %v", path, err)
+	}
+
+	return native, nil
+}
+
+// getBytesDefaultValue converts the default value of a bytes field, as the
+// avro spec says every code point of the string is a byte, e.g. "\u00ff" is 0xff
+func getBytesDefaultValue(value string, path string) ([]byte, error) {
+	native := make([]byte, 0, len(value))
+	for _, r := range value {
+		if r > 0xff {
+			return nil, fmt.Errorf("invalid default value for field \"%s\": value \"%s\" has code points out of the range of bytes", path, value)
+		}
+		native = append(native, byte(r))
+	}
+
+	return native, nil
+}
+
+func getRecordNativeDefaultValue(field *Field, value interface{}, path string) (interface{}, error) {
+	valueAsMap, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid default value for field \"%s\": value \"%v\" is not of type \"record\"", path, value)
+	}
+
+	native := make(map[string]interface{}, len(field.Fields))
+	for _, v := range field.Fields {
+		if v.CatchAll {
+			native[v.Name] = map[string]interface{}{}
+			continue
+		}
+		fieldValue, ok := valueAsMap[v.Name]
+		if !ok {
+			if !v.HasDefault {
+				return nil, fmt.Errorf("invalid default value for field \"%s\": value for field \"%s\" not found", path, v.Name)
+			}
+			fieldValue = v.DefaultValue
+		}
+
+		n, err := getNativeDefaultValue(v, fieldValue, path+"."+v.Name)
+		if err != nil {
+			return nil, err
+		}
+		native[v.Name] = n
+	}
+
+	return native, nil
+}
+
+// getRecordDefaultFromFields builds the default value of a record without default
+// from the defaults of its fields, only if all of them have one
+func getRecordDefaultFromFields(field *Field) (interface{}, bool) {
+	native := make(map[string]interface{}, len(field.Fields))
+	for _, v := range field.Fields {
+		if !v.HasNativeDefault {
+			return nil, false
+		}
+		native[v.Name] = copyNativeValue(v.NativeDefault)
+	}
+
+	return native, true
+}
+
+func isRecordField(field *Field) bool {
+	return field.Type == types.Primitive && field.TypeValue == types.RecordType
+}
+
+// copyNativeValue returns a copy of the mutable parts of a native value, so
+// records using the same default value don't share them
+func copyNativeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		c := make([]byte, len(v))
+		copy(c, v)
+		return c
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			c[key] = copyNativeValue(item)
+		}
+		return c
+	default:
+		return value
+	}
+}
@@ -0,0 +1,268 @@
+package kedavro
+
+import (
+	"testing"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+const defaultsSchema = `
+{
+	"name": "Wizard",
+	"type": "record",
+	"fields": [
+		{
+			"name": "name",
+			"type": "string"
+		},
+		{
+			"name": "house",
+			"type": "string",
+			"default": "hufflepuff"
+		},
+		{
+			"name": "spells",
+			"type": "int",
+			"default": 3
+		},
+		{
+			"name": "power",
+			"type": "float",
+			"default": 1.5
+		},
+		{
+			"name": "wand",
+			"type": "bytes",
+			"default": "holly"
+		},
+		{
+			"name": "born",
+			"type": "long",
+			"logicalType": "timestamp-millis",
+			"default": 1571128870000
+		},
+		{
+			"name": "last_seen",
+			"type": {
+				"type": "long",
+				"logicalType": "timestamp-micros"
+			},
+			"default": 1571128870000000
+		},
+		{
+			"name": "address",
+			"type": "record",
+			"default": {"street": "privet drive"},
+			"fields": [
+				{
+					"name": "street",
+					"type": "string"
+				},
+				{
+					"name": "number",
+					"type": "int",
+					"default": 4
+				}
+			]
+		}
+	]
+}
+`
+
+func TestDefaultValues(t *testing.T) {
+	p, err := NewParser(defaultsSchema, WithTimestampToMillis(), WithStringToNumber())
+	assert.NoError(t, err)
+
+	result, err := p.Parse([]byte(`{"name": "Cedric", "address": {"street": "ottery st catchpole"}}`))
+	assert.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"name":      "Cedric",
+		"house":     "hufflepuff",
+		"spells":    int32(3),
+		"power":     float32(1.5),
+		"wand":      []byte("holly"),
+		"born":      time.Unix(0, 1571128870000*int64(time.Millisecond)),
+		"last_seen": time.Unix(0, 1571128870000000*int64(time.Microsecond)),
+		"address": map[string]interface{}{
+			"street": "ottery st catchpole",
+			"number": int32(4),
+		},
+	}
+	assert.Equal(t, expected, result)
+}
+
+func TestDefaultValuesAreNotShared(t *testing.T) {
+	p, err := NewParser(defaultsSchema)
+	assert.NoError(t, err)
+
+	first, err := p.Parse([]byte(`{"name": "Cedric", "address": {"street": "ottery st catchpole"}}`))
+	assert.NoError(t, err)
+	first.(map[string]interface{})["wand"].([]byte)[0] = 'j'
+
+	second, err := p.Parse([]byte(`{"name": "Cedric", "address": {"street": "ottery st catchpole"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("holly"), second.(map[string]interface{})["wand"])
+}
+
+func TestBytesDefaultValues(t *testing.T) {
+	schema := `{"name": "Test", "type": "record", "fields": [
+		{"name": "wand", "type": "bytes", "default": "\u00ff\u0000h\u00e9"},
+		{"name": "cloak", "type": ["bytes", "null"], "default": "\u00ff"}
+	]}`
+
+	p, err := NewParser(schema)
+	assert.NoError(t, err)
+
+	result, err := p.Parse([]byte(`{}`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"wand":  []byte{0xff, 0x00, 'h', 0xe9},
+		"cloak": map[string]interface{}{"bytes": []byte{0xff}},
+	}, result)
+
+	// the avro JSON encoding of bytes uses the same code points
+	textual, err := p.ParseToTextual(nil, []byte(`{}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"wand\":\"\u00ff\\u0000h\u00e9\",\"cloak\":{\"bytes\":\"\u00ff\"}}", string(textual))
+}
+
+func TestUnionDefaultValues(t *testing.T) {
+	schema := `
+	{
+		"name": "Wizard",
+		"type": "record",
+		"fields": [
+			{
+				"name": "house",
+				"type": ["string", "null"],
+				"default": "unknown"
+			},
+			{
+				"name": "points",
+				"type": ["long", "null"],
+				"default": 10
+			},
+			{
+				"name": "wand",
+				"type": ["null", "string"],
+				"default": null
+			}
+		]
+	}
+	`
+
+	p, err := NewParser(schema)
+	assert.NoError(t, err)
+
+	result, err := p.Parse([]byte(`{"points": null}`))
+	assert.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"house":  map[string]interface{}{"string": "unknown"},
+		"points": nil,
+		"wand":   nil,
+	}
+	assert.Equal(t, expected, result)
+
+	result, err = p.Parse([]byte(`{"house": "gryffindor"}`))
+	assert.NoError(t, err)
+
+	expected = map[string]interface{}{
+		"house":  map[string]interface{}{"string": "gryffindor"},
+		"points": map[string]interface{}{"long": int64(10)},
+		"wand":   nil,
+	}
+	assert.Equal(t, expected, result)
+
+	codec, err := goavro.NewCodec(schema)
+	assert.NoError(t, err)
+
+	textual, err := codec.TextualFromNative(nil, result)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"house": {"string": "gryffindor"}, "points": {"long": 10}, "wand": null}`, string(textual))
+}
+
+func TestInvalidDefaultValues(t *testing.T) {
+	type testItem struct {
+		field string
+		path  string
+	}
+
+	tests := []testItem{
+		{
+			field: `{"name": "test", "type": "long", "default": "abc"}`,
+			path:  "$.test",
+		},
+		{
+			field: `{"name": "test", "type": "long", "default": 1.5}`,
+			path:  "$.test",
+		},
+		{
+			field: `{"name": "test", "type": "int", "default": 4294967296}`,
+			path:  "$.test",
+		},
+		{
+			field: `{"name": "test", "type": "string", "default": 1234}`,
+			path:  "$.test",
+		},
+		{
+			field: `{"name": "test", "type": "boolean", "default": "true"}`,
+			path:  "$.test",
+		},
+		{
+			field: `{"name": "test", "type": "bytes", "default": "\u0100"}`,
+			path:  "$.test",
+		},
+		{
+			field: `{"name": "test", "type": "bytes", "default": 1234}`,
+			path:  "$.test",
+		},
+		{
+			field: `{"name": "test", "type": "null", "default": "bleh"}`,
+			path:  "$.test",
+		},
+		{
+			field: `{"name": "test", "type": "long", "logicalType": "timestamp-millis", "default": "2019-10-14T12:45:18Z"}`,
+			path:  "$.test",
+		},
+		{
+			field: `{"name": "test", "type": {"type": "float"}, "default": "bleh"}`,
+			path:  "$.test",
+		},
+		{
+			field: `{"name": "test", "type": ["long", "null"], "default": "bleh"}`,
+			path:  "$.test",
+		},
+		{
+			field: `{"name": "test", "type": ["long", "null"], "default": null}`,
+			path:  "$.test",
+		},
+		{
+			field: `{"name": "test", "type": "record", "fields": [{"name": "inner", "type": "int", "default": "bleh"}]}`,
+			path:  "$.test.inner",
+		},
+		{
+			field: `{"name": "test", "type": "record", "default": "bleh", "fields": [{"name": "inner", "type": "int"}]}`,
+			path:  "$.test",
+		},
+		{
+			field: `{"name": "test", "type": "record", "default": {}, "fields": [{"name": "inner", "type": "int"}]}`,
+			path:  "$.test",
+		},
+		{
+			field: `{"name": "test", "type": "record", "default": {"inner": "bleh"}, "fields": [{"name": "inner", "type": "int"}]}`,
+			path:  "$.test.inner",
+		},
+	}
+
+	for _, v := range tests {
+		schema := `{"name": "Test", "type": "record", "fields": [` + v.field + `]}`
+		_, err := NewParser(schema, WithStringToNumber(), WithStringToBool(), WithDateTimeFormat(time.RFC3339))
+		if assert.Error(t, err, v.field) {
+			assert.Contains(t, err.Error(), "\""+v.path+"\"", v.field)
+		}
+	}
+}
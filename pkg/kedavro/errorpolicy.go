@@ -0,0 +1,97 @@
+package kedavro
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ouzi-dev/avro-kedavro/pkg/types"
+)
+
+// onErrorAttribute is the attribute with the error policy of a field
+const onErrorAttribute = "kedavro.onError"
+
+var errorPolicies = map[string]types.ErrorPolicy{
+	"fail":       types.ErrorPolicyFail,
+	"null":       types.ErrorPolicyNull,
+	"default":    types.ErrorPolicyDefault,
+	"deadLetter": types.ErrorPolicyDeadLetter,
+}
+
+func getOnError(fieldMap map[string]interface{}) (types.ErrorPolicy, bool, error) {
+	onErrorValue, ok := fieldMap[onErrorAttribute]
+	if !ok {
+		return types.ErrorPolicyFail, false, nil
+	}
+
+	name, _ := onErrorValue.(string)
+	policy, ok := errorPolicies[name]
+	if !ok {
+		return types.ErrorPolicyFail, false, fmt.Errorf("%s has to be one of \"fail\", \"null\", \"default\" or \"deadLetter\", but it's current value is: %v", onErrorAttribute, onErrorValue)
+	}
+
+	return policy, true, nil
+}
+
+func isNullableField(field *Field) bool {
+	// all the supported unions have a "null" type
+	return field.Type == types.Union || (field.Type == types.Primitive && field.TypeValue == types.NilType)
+}
+
+// getFieldErrorPolicy returns the error policy of the field, or the policy of
+// the parser if the field can use it
+func getFieldErrorPolicy(field *Field) types.ErrorPolicy {
+	if field.HasOnError {
+		return field.OnError
+	}
+
+	policy := field.Opts.ErrorPolicy
+	switch {
+	case policy == types.ErrorPolicyNull && !isNullableField(field):
+		return types.ErrorPolicyFail
+	case policy == types.ErrorPolicyDefault && !field.HasNativeDefault:
+		return types.ErrorPolicyFail
+	default:
+		return policy
+	}
+}
+
+// isReplacedByErrorPolicy returns if the error policy of the field replaces its
+// value when parsing it fails with err
+func isReplacedByErrorPolicy(field *Field, err error) bool {
+	if errors.Is(err, ErrDeadLetter) {
+		return false
+	}
+	policy := getFieldErrorPolicy(field)
+	return policy == types.ErrorPolicyNull || policy == types.ErrorPolicyDefault
+}
+
+// applyErrorPolicy decides what to do with the error of a field, it returns
+// the value to use instead or the error if the record has to fail
+func applyErrorPolicy(field *Field, state *parseState, value interface{}, err error) (interface{}, error) {
+	if errors.Is(err, ErrDeadLetter) {
+		// a nested field already marked the record for dead letter
+		return value, err
+	}
+
+	switch getFieldErrorPolicy(field) {
+	case types.ErrorPolicyNull:
+		state.addCoercion(CoercionErrorPolicyNull, newParseError(field, state, err).Value, nil)
+		return nil, nil
+	case types.ErrorPolicyDefault:
+		state.addCoercion(CoercionErrorPolicyDefault, newParseError(field, state, err).Value, field.NativeDefault)
+		return copyNativeValue(field.NativeDefault), nil
+	case types.ErrorPolicyDeadLetter:
+		var nestedErrs ParseErrors
+		if errors.As(err, &nestedErrs) {
+			for _, v := range nestedErrs {
+				v.DeadLetter = true
+			}
+			return value, nestedErrs
+		}
+		parseErr := newParseError(field, state, err)
+		parseErr.DeadLetter = true
+		return value, parseErr
+	default:
+		return value, err
+	}
+}
@@ -0,0 +1,196 @@
+package kedavro
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ouzi-dev/avro-kedavro/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const errorPolicySchema = `
+{
+	"name": "Order",
+	"type": "record",
+	"fields": [
+		{
+			"name": "id",
+			"type": "long"
+		},
+		{
+			"name": "coupon",
+			"type": ["null", "string"],
+			"kedavro.onError": "null"
+		},
+		{
+			"name": "units",
+			"type": "int",
+			"default": 1,
+			"kedavro.onError": "default"
+		},
+		{
+			"name": "price",
+			"type": "double",
+			"kedavro.onError": "deadLetter"
+		},
+		{
+			"name": "notes",
+			"type": ["null", "string"],
+			"default": null
+		},
+		{
+			"name": "shipping",
+			"type": "record",
+			"default": {"method": "standard", "days": 5},
+			"kedavro.onError": "default",
+			"fields": [
+				{
+					"name": "method",
+					"type": "string"
+				},
+				{
+					"name": "days",
+					"type": "int"
+				}
+			]
+		}
+	]
+}
+`
+
+func TestErrorPolicies(t *testing.T) {
+	p, err := NewParser(errorPolicySchema)
+	assert.NoError(t, err)
+
+	result, err := p.Parse([]byte(`{"id": 1, "coupon": 10, "units": "many", "price": 9.99, "shipping": {"method": "express", "days": "tomorrow"}}`))
+	assert.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"id":     int64(1),
+		"coupon": nil,
+		"units":  int32(1),
+		"price":  float64(9.99),
+		"notes":  nil,
+		"shipping": map[string]interface{}{
+			"method": "standard",
+			"days":   int32(5),
+		},
+	}
+	assert.Equal(t, expected, result)
+
+	// fields without error policy fail
+	_, err = p.Parse([]byte(`{"id": "one", "price": 9.99}`))
+	var parseErr *ParseError
+	if assert.True(t, errors.As(err, &parseErr)) {
+		assert.Equal(t, "$.id", parseErr.Path)
+		assert.False(t, parseErr.DeadLetter)
+	}
+	assert.False(t, errors.Is(err, ErrDeadLetter))
+
+	// dead letter
+	result, err = p.Parse([]byte(`{"id": 1, "price": "free"}`))
+	assert.Nil(t, result)
+	assert.True(t, errors.Is(err, ErrDeadLetter))
+	if assert.True(t, errors.As(err, &parseErr)) {
+		assert.Equal(t, "$.price", parseErr.Path)
+		assert.True(t, parseErr.DeadLetter)
+	}
+}
+
+func TestGlobalErrorPolicy(t *testing.T) {
+	p, err := NewParser(errorPolicySchema, WithErrorPolicy(types.ErrorPolicyNull))
+	assert.NoError(t, err)
+
+	// notes is nullable so it uses the policy of the parser
+	result, err := p.Parse([]byte(`{"id": 1, "price": 9.99, "notes": 1234}`))
+	assert.NoError(t, err)
+	assert.Nil(t, result.(map[string]interface{})["notes"])
+
+	// id is not nullable, so it fails
+	_, err = p.Parse([]byte(`{"id": "one", "price": 9.99}`))
+	assert.Error(t, err)
+
+	// the policy of the field is used before the policy of the parser
+	_, err = p.Parse([]byte(`{"id": 1, "price": "free"}`))
+	assert.True(t, errors.Is(err, ErrDeadLetter))
+
+	p, err = NewParser(errorPolicySchema, WithErrorPolicy(types.ErrorPolicyDeadLetter), WithAllErrors())
+	assert.NoError(t, err)
+
+	result, err = p.Parse([]byte(`{"id": "one", "price": "free", "units": 2}`))
+	assert.True(t, errors.Is(err, ErrDeadLetter))
+	assert.Equal(t, int32(2), result.(map[string]interface{})["units"])
+
+	var parseErrs ParseErrors
+	if assert.True(t, errors.As(err, &parseErrs)) && assert.Len(t, parseErrs, 2) {
+		assert.Equal(t, "$.id", parseErrs[0].Path)
+		assert.True(t, parseErrs[0].DeadLetter)
+		assert.Equal(t, "$.price", parseErrs[1].Path)
+		assert.True(t, parseErrs[1].DeadLetter)
+
+		// ParseErrors answers ErrDeadLetter itself when any of its errors is marked
+		assert.True(t, parseErrs.Is(ErrDeadLetter))
+		parseErrs[0].DeadLetter = false
+		assert.True(t, parseErrs.Is(ErrDeadLetter))
+		parseErrs[1].DeadLetter = false
+		assert.False(t, parseErrs.Is(ErrDeadLetter))
+	}
+}
+
+func TestDeadLetterInNestedRecord(t *testing.T) {
+	schema := `
+	{
+		"name": "Order",
+		"type": "record",
+		"fields": [
+			{
+				"name": "shipping",
+				"type": "record",
+				"default": {"days": 5},
+				"kedavro.onError": "default",
+				"fields": [
+					{
+						"name": "days",
+						"type": "int",
+						"kedavro.onError": "deadLetter"
+					}
+				]
+			}
+		]
+	}
+	`
+
+	p, err := NewParser(schema)
+	assert.NoError(t, err)
+
+	// the dead letter of the nested field can't be replaced by the default of the record
+	_, err = p.Parse([]byte(`{"shipping": {"days": "tomorrow"}}`))
+	assert.True(t, errors.Is(err, ErrDeadLetter))
+
+	var parseErr *ParseError
+	if assert.True(t, errors.As(err, &parseErr)) {
+		assert.Equal(t, "$.shipping.days", parseErr.Path)
+	}
+}
+
+func TestInvalidErrorPolicies(t *testing.T) {
+	tests := []string{
+		`{"name": "test", "type": "string", "kedavro.onError": "null"}`,
+		`{"name": "test", "type": "string", "kedavro.onError": "default"}`,
+		`{"name": "test", "type": "string", "kedavro.onError": "ignore"}`,
+		`{"name": "test", "type": "string", "kedavro.onError": true}`,
+		`{"name": "test", "type": {"type": "long"}, "kedavro.onError": "null"}`,
+		`{"name": "test", "type": "record", "kedavro.onError": "default", "fields": [{"name": "inner", "type": "int"}]}`,
+	}
+
+	for _, v := range tests {
+		schema := `{"name": "Test", "type": "record", "fields": [` + v + `]}`
+		_, err := NewParser(schema)
+		assert.Error(t, err, v)
+	}
+
+	// records built from the defaults of their fields have a default value
+	schema := `{"name": "Test", "type": "record", "fields": [{"name": "test", "type": "record", "kedavro.onError": "default", "fields": [{"name": "inner", "type": "int", "default": 1}]}]}`
+	_, err := NewParser(schema, WithRecordDefaultFromFields())
+	assert.NoError(t, err)
+}
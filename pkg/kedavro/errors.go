@@ -0,0 +1,173 @@
+package kedavro
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ouzi-dev/avro-kedavro/pkg/types"
+)
+
+// ReasonCode is the cause of a ParseError
+type ReasonCode string
+
+const (
+	// ReasonMissingValue the field is not in the record and it doesn't have a default value
+	ReasonMissingValue ReasonCode = "missing_value"
+	// ReasonTypeMismatch the value is not of the type of the field
+	ReasonTypeMismatch ReasonCode = "type_mismatch"
+	// ReasonInvalidValue the value can't be converted to the type of the field
+	ReasonInvalidValue ReasonCode = "invalid_value"
+	// ReasonUnknownField the record has keys not used by any field in strict mode
+	ReasonUnknownField ReasonCode = "unknown_field"
+	// ReasonAmbiguousKey more than one key of the record matches the field
+	ReasonAmbiguousKey ReasonCode = "ambiguous_key"
+	// ReasonInvalidJSON the record, or a record embedded as a string, is not valid JSON
+	ReasonInvalidJSON ReasonCode = "invalid_json"
+)
+
+// ParseError is the error returned when a record can't be parsed, Path is the
+// JSON path of the field that failed, e.g. "$.order.price"
+type ParseError struct {
+	Path       string
+	SchemaType string
+	Value      interface{}
+	Reason     ReasonCode
+	Err        error
+	// DeadLetter is true when the record has to be sent to the dead letter queue
+	// because of the error policy of the field, errors.Is(err, ErrDeadLetter)
+	// returns true for these errors
+	DeadLetter bool
+}
+
+// ErrDeadLetter is the error for records that failed in a field with the dead
+// letter error policy
+var ErrDeadLetter = errors.New("record marked for dead letter")
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("field parse error, path: \"%s\", error: %v", e.Path, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ParseError) Is(target error) bool {
+	return e.DeadLetter && target == ErrDeadLetter
+}
+
+// ParseErrors are all the errors found in a record when the parser collects
+// all of them instead of failing with the first one
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, v := range e {
+		messages = append(messages, v.Error())
+	}
+	return fmt.Sprintf("%d errors parsing record: %s", len(e), strings.Join(messages, "; "))
+}
+
+// Is and As check every error, errors.Is and errors.As only follow Unwrap() []error
+// from go 1.20. errors.Is(err, ErrDeadLetter) is true if any error is marked for
+// dead letter
+func (e ParseErrors) Is(target error) bool {
+	for _, v := range e {
+		if errors.Is(v, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e ParseErrors) As(target interface{}) bool {
+	for _, v := range e {
+		if errors.As(v, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e ParseErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, v := range e {
+		errs = append(errs, v)
+	}
+	return errs
+}
+
+// isAllErrors returns true if the parser has to collect all the errors of the
+// record, with the option or when explaining the record
+func isAllErrors(field *Field, state *parseState) bool {
+	return field.Opts.IsAllErrors || state.isExplain()
+}
+
+// appendParseErrors adds the errors of a field, a nested record can have more than one
+func appendParseErrors(errs ParseErrors, field *Field, state *parseState, err error) ParseErrors {
+	var nestedErrs ParseErrors
+	if errors.As(err, &nestedErrs) {
+		return append(errs, nestedErrs...)
+	}
+	return append(errs, newParseError(field, state, err))
+}
+
+// reasonError is the error of a parser that doesn't know the path of the
+// field yet, it's converted to a ParseError by the record parser
+type reasonError struct {
+	reason ReasonCode
+	value  interface{}
+	err    error
+}
+
+func newReasonError(reason ReasonCode, value interface{}, err error) error {
+	return &reasonError{reason: reason, value: value, err: err}
+}
+
+func (e *reasonError) Error() string {
+	return e.err.Error()
+}
+
+func (e *reasonError) Unwrap() error {
+	return e.err
+}
+
+// newParseError returns the error of a field as a ParseError, errors of nested
+// records are already ParseErrors with their own path
+func newParseError(field *Field, state *parseState, err error) *ParseError {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return parseErr
+	}
+
+	parseErr = &ParseError{
+		Path:       state.path(),
+		SchemaType: getSchemaType(field),
+		Reason:     ReasonInvalidValue,
+		Err:        err,
+	}
+
+	var reasonErr *reasonError
+	if errors.As(err, &reasonErr) {
+		parseErr.Reason = reasonErr.reason
+		parseErr.Value = reasonErr.value
+		parseErr.Err = reasonErr.err
+	}
+
+	return parseErr
+}
+
+// getSchemaType returns the name of the type of the field, its logical type if
+// it has one, or "union" for unions
+func getSchemaType(field *Field) string {
+	if field.Type == types.Union {
+		return "union"
+	}
+	if len(field.LogicalType) > 0 {
+		return field.LogicalType
+	}
+	if typeName, ok := field.TypeValue.(string); ok {
+		return typeName
+	}
+	return ""
+}
@@ -0,0 +1,277 @@
+package kedavro
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ouzi-dev/avro-kedavro/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseError(t *testing.T) {
+	schema := `
+	{
+		"name": "Order",
+		"type": "record",
+		"fields": [
+			{
+				"name": "id",
+				"type": "long",
+				"aliases": ["order_id"]
+			},
+			{
+				"name": "customer",
+				"type": ["null", "string"]
+			},
+			{
+				"name": "created",
+				"type": "long",
+				"logicalType": "timestamp-millis",
+				"default": 0
+			},
+			{
+				"name": "item",
+				"type": "record",
+				"fields": [
+					{
+						"name": "price",
+						"type": "double"
+					},
+					{
+						"name": "details",
+						"type": "record",
+						"fields": [
+							{
+								"name": "color",
+								"type": "string"
+							}
+						]
+					}
+				]
+			}
+		]
+	}
+	`
+
+	type testItem struct {
+		record     string
+		path       string
+		schemaType string
+		value      interface{}
+		reason     ReasonCode
+	}
+
+	tests := []testItem{
+		{
+			record:     `{"id": 1, "customer": null, "item": {"price": true, "details": {"color": "red"}}}`,
+			path:       "$.item.price",
+			schemaType: "double",
+			value:      true,
+			reason:     ReasonTypeMismatch,
+		},
+		{
+			record:     `{"id": 1, "customer": null, "item": {"price": 10, "details": {}}}`,
+			path:       "$.item.details.color",
+			schemaType: "string",
+			reason:     ReasonMissingValue,
+		},
+		{
+			record:     `{"id": 1, "customer": null, "item": {"price": 10, "details": "red"}}`,
+			path:       "$.item.details",
+			schemaType: "record",
+			value:      "red",
+			reason:     ReasonTypeMismatch,
+		},
+		{
+			record:     `{"id": 1, "customer": 1234, "item": {"price": 10, "details": {"color": "red"}}}`,
+			path:       "$.customer",
+			schemaType: "union",
+			value:      float64(1234),
+			reason:     ReasonTypeMismatch,
+		},
+		{
+			record:     `{"id": 1, "customer": null, "created": "yesterday", "item": {"price": 10, "details": {"color": "red"}}}`,
+			path:       "$.created",
+			schemaType: "timestamp-millis",
+			value:      "yesterday",
+			reason:     ReasonInvalidValue,
+		},
+		{
+			record:     `{"id": "one", "customer": null, "item": {"price": 10, "details": {"color": "red"}}}`,
+			path:       "$.id",
+			schemaType: "long",
+			value:      "one",
+			reason:     ReasonInvalidValue,
+		},
+		{
+			record:     `{"id": 1, "order_id": 2, "customer": null, "item": {"price": 10, "details": {"color": "red"}}}`,
+			path:       "$.id",
+			schemaType: "long",
+			reason:     ReasonAmbiguousKey,
+		},
+		{
+			record:     `{"id": 1, "customer": null, "item": {"price": 10, "details": {"color": "red", "size": "xl"}}}`,
+			path:       "$.item.details",
+			schemaType: "record",
+			value:      []string{"size"},
+			reason:     ReasonUnknownField,
+		},
+		{
+			record:     `{"id": 1,`,
+			path:       "$",
+			schemaType: "record",
+			reason:     ReasonInvalidJSON,
+		},
+	}
+
+	p, err := NewParser(schema, WithStringToNumber(), WithAliasConflictPolicy(types.ConflictError), WithStrictFields())
+	assert.NoError(t, err)
+
+	for _, v := range tests {
+		_, err := p.Parse([]byte(v.record))
+
+		var parseErr *ParseError
+		if assert.True(t, errors.As(err, &parseErr), v.record) {
+			assert.Equal(t, v.path, parseErr.Path, v.record)
+			assert.Equal(t, v.schemaType, parseErr.SchemaType, v.record)
+			assert.Equal(t, v.value, parseErr.Value, v.record)
+			assert.Equal(t, v.reason, parseErr.Reason, v.record)
+			assert.Contains(t, err.Error(), "\""+v.path+"\"", v.record)
+			assert.NotContains(t, err.Error(), "0x", v.record)
+		}
+	}
+}
+
+func TestAllErrors(t *testing.T) {
+	schema := `
+	{
+		"name": "Order",
+		"type": "record",
+		"fields": [
+			{
+				"name": "id",
+				"type": "long"
+			},
+			{
+				"name": "customer",
+				"type": "string",
+				"aliases": ["client"]
+			},
+			{
+				"name": "total",
+				"type": "double"
+			},
+			{
+				"name": "item",
+				"type": "record",
+				"fields": [
+					{
+						"name": "name",
+						"type": "string"
+					},
+					{
+						"name": "price",
+						"type": "double"
+					},
+					{
+						"name": "units",
+						"type": "int"
+					}
+				]
+			}
+		]
+	}
+	`
+
+	record := `{"id": "one", "customer": "harry", "client": "ron", "total": 10, "item": {"name": "wand", "price": "cheap", "color": "red"}}`
+
+	p, err := NewParser(schema, WithAllErrors(), WithStrictFields(), WithAliasConflictPolicy(types.ConflictError))
+	assert.NoError(t, err)
+
+	result, err := p.Parse([]byte(record))
+
+	expected := map[string]interface{}{
+		"total": float64(10),
+		"item": map[string]interface{}{
+			"name": "wand",
+		},
+	}
+	assert.Equal(t, expected, result)
+
+	var parseErrs ParseErrors
+	if assert.True(t, errors.As(err, &parseErrs)) {
+		type errorItem struct {
+			path   string
+			reason ReasonCode
+		}
+		var found []errorItem
+		for _, v := range parseErrs {
+			found = append(found, errorItem{path: v.Path, reason: v.Reason})
+		}
+		assert.Equal(t, []errorItem{
+			{path: "$.id", reason: ReasonTypeMismatch},
+			{path: "$.customer", reason: ReasonAmbiguousKey},
+			{path: "$.item", reason: ReasonUnknownField},
+			{path: "$.item.price", reason: ReasonTypeMismatch},
+			{path: "$.item.units", reason: ReasonMissingValue},
+		}, found)
+	}
+
+	// the first error can be found as a ParseError too
+	var parseErr *ParseError
+	if assert.True(t, errors.As(err, &parseErr)) {
+		assert.Equal(t, "$.id", parseErr.Path)
+	}
+
+	// without depending on errors.As following Unwrap() []error, only from go 1.20
+	parseErr = nil
+	if assert.True(t, parseErrs.As(&parseErr)) {
+		assert.Equal(t, "$.id", parseErr.Path)
+	}
+	assert.True(t, parseErrs.Is(parseErrs[1]))
+	assert.False(t, parseErrs.Is(ErrDeadLetter))
+	var reasonErr *reasonError
+	assert.False(t, parseErrs.As(&reasonErr))
+
+	// without the option the parser fails with the first error
+	p, err = NewParser(schema, WithStrictFields(), WithAliasConflictPolicy(types.ConflictError))
+	assert.NoError(t, err)
+
+	result, err = p.Parse([]byte(record))
+	assert.Nil(t, result)
+	assert.False(t, errors.As(err, &parseErrs))
+	if assert.True(t, errors.As(err, &parseErr)) {
+		assert.Equal(t, "$.customer", parseErr.Path)
+	}
+}
+
+func TestAllErrorsWithNormalizedKeys(t *testing.T) {
+	schema := `
+	{
+		"name": "Wizard",
+		"type": "record",
+		"fields": [
+			{
+				"name": "user_name",
+				"type": "string"
+			},
+			{
+				"name": "house",
+				"type": "string"
+			}
+		]
+	}
+	`
+
+	p, err := NewParser(schema, WithAllErrors(), WithNormalizedKeys())
+	assert.NoError(t, err)
+
+	result, err := p.Parse([]byte(`{"userName": "harry", "UserName": "ron", "user-name": "hermione", "house": "gryffindor"}`))
+	assert.Equal(t, map[string]interface{}{"house": "gryffindor"}, result)
+
+	var parseErrs ParseErrors
+	if assert.True(t, errors.As(err, &parseErrs)) && assert.Len(t, parseErrs, 1) {
+		assert.Equal(t, "$.user_name", parseErrs[0].Path)
+		assert.Equal(t, ReasonAmbiguousKey, parseErrs[0].Reason)
+	}
+}
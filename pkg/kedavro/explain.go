@@ -0,0 +1,171 @@
+package kedavro
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ouzi-dev/avro-kedavro/pkg/types"
+)
+
+// ExplainNode is the explanation of how a field of the schema was parsed from
+// the record, the node of a record has the nodes of its fields as children
+type ExplainNode struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	SchemaType string `json:"schemaType"`
+	// SourceKey is the key, alias or source path where the value was found
+	SourceKey string `json:"sourceKey,omitempty"`
+	// Found is false if the field wasn't in the record
+	Found bool `json:"found"`
+	// RawValue is the value in the record, not set for records
+	RawValue interface{} `json:"rawValue,omitempty"`
+	// Branch is the type of the union used for the value
+	Branch      string         `json:"branch,omitempty"`
+	Coercions   []CoercionRule `json:"coercions,omitempty"`
+	DefaultUsed bool           `json:"defaultUsed,omitempty"`
+	Reason      ReasonCode     `json:"reason,omitempty"`
+	Error       string         `json:"error,omitempty"`
+	Children    []*ExplainNode `json:"children,omitempty"`
+	// sourceKeys are the keys of the fields of a record, until their nodes are created
+	sourceKeys map[string]string
+}
+
+// String renders the explanation as text, one line per field
+func (n *ExplainNode) String() string {
+	var sb strings.Builder
+	n.write(&sb, 0)
+	return sb.String()
+}
+
+func (n *ExplainNode) write(sb *strings.Builder, depth int) {
+	var details []string
+	if len(n.SourceKey) > 0 {
+		details = append(details, fmt.Sprintf("key \"%s\"", n.SourceKey))
+	}
+	if n.RawValue != nil || (n.Found && len(n.Error) == 0 && n.SchemaType != types.RecordType && n.SchemaType != types.MapType) {
+		details = append(details, fmt.Sprintf("value %s", formatRawValue(n.RawValue)))
+	}
+	if !n.Found {
+		details = append(details, "missing")
+	}
+	if len(n.Branch) > 0 {
+		details = append(details, fmt.Sprintf("branch \"%s\"", n.Branch))
+	}
+	for _, v := range n.Coercions {
+		details = append(details, fmt.Sprintf("coercion %s", v))
+	}
+	if n.DefaultUsed {
+		details = append(details, "default used")
+	}
+	if len(n.Error) > 0 {
+		details = append(details, fmt.Sprintf("error %s: %s", n.Reason, n.Error))
+	}
+
+	sb.WriteString(strings.Repeat("  ", depth))
+	sb.WriteString(fmt.Sprintf("%s (%s)", n.Name, n.SchemaType))
+	if len(details) > 0 {
+		sb.WriteString(": ")
+		sb.WriteString(strings.Join(details, ", "))
+	}
+	sb.WriteString("\n")
+
+	for _, v := range n.Children {
+		v.write(sb, depth+1)
+	}
+}
+
+func formatRawValue(value interface{}) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(b)
+}
+
+func newExplainRoot(field *Field) *ExplainNode {
+	return &ExplainNode{
+		Name:       field.Name,
+		Path:       "$",
+		SchemaType: types.RecordType,
+		Found:      true,
+	}
+}
+
+func (s *parseState) isExplain() bool {
+	return s != nil && s.node != nil
+}
+
+// explainSourceKey keeps the key used for a field of the record, the node of
+// the field is created later when the field is parsed
+func (s *parseState) explainSourceKey(name, key string) {
+	if !s.isExplain() {
+		return
+	}
+	if s.node.sourceKeys == nil {
+		s.node.sourceKeys = map[string]string{}
+	}
+	s.node.sourceKeys[name] = key
+}
+
+// explainField creates the node of a field in the node of its record
+func (s *parseState) explainField(field *Field, values map[string]interface{}) {
+	if !s.parent.isExplain() {
+		return
+	}
+
+	value, found := values[field.Name]
+	s.node = &ExplainNode{
+		Name:       field.Name,
+		Path:       s.path(),
+		SchemaType: getSchemaType(field),
+		SourceKey:  s.parent.node.sourceKeys[field.Name],
+		Found:      found,
+	}
+	delete(s.parent.node.sourceKeys, field.Name)
+	if len(s.parent.node.sourceKeys) == 0 {
+		s.parent.node.sourceKeys = nil
+	}
+	switch value.(type) {
+	case map[string]interface{}, *ParseError:
+		// records have the nodes of their fields, and errors are not values of the record
+	default:
+		s.node.RawValue = value
+	}
+	s.parent.node.Children = append(s.parent.node.Children, s.node)
+}
+
+func (s *parseState) explainBranch(branch string) {
+	if s.isExplain() {
+		s.node.Branch = branch
+	}
+}
+
+// explainError sets the error of the node, only if it's the error of this
+// field and not of one of its children
+func (s *parseState) explainError(err *ParseError) {
+	if !s.isExplain() || err.Path != s.node.Path || len(s.node.Error) > 0 {
+		return
+	}
+	s.node.Reason = err.Reason
+	s.node.Error = err.Err.Error()
+}
+
+func (s *parseState) explainResult(field *Field, err error) {
+	if !s.isExplain() {
+		return
+	}
+
+	if err != nil {
+		var nestedErrs ParseErrors
+		if !errors.As(err, &nestedErrs) {
+			s.explainError(newParseError(field, s, err))
+		}
+		return
+	}
+
+	if !s.node.Found && len(s.node.Coercions) == 0 {
+		s.node.DefaultUsed = true
+	}
+}
@@ -0,0 +1,144 @@
+package kedavro
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const explainSchema = `
+{
+	"name": "Order",
+	"type": "record",
+	"fields": [
+		{
+			"name": "id",
+			"type": "long",
+			"aliases": ["order_id"]
+		},
+		{
+			"name": "coupon",
+			"type": ["null", "string"]
+		},
+		{
+			"name": "total",
+			"type": ["double", "null"],
+			"default": 0
+		},
+		{
+			"name": "units",
+			"type": "int",
+			"default": 1
+		},
+		{
+			"name": "user",
+			"type": "string",
+			"kedavro.source": "$.customer.name"
+		},
+		{
+			"name": "shipping",
+			"type": "record",
+			"fields": [
+				{
+					"name": "method",
+					"type": "string"
+				},
+				{
+					"name": "days",
+					"type": "int"
+				}
+			]
+		}
+	]
+}
+`
+
+func TestExplain(t *testing.T) {
+	p, err := NewParser(explainSchema, WithStringToNumber())
+	assert.NoError(t, err)
+
+	record := `{"order_id": "1234", "coupon": "SUMMER", "customer": {"name": "harry"}, "shipping": {"method": "owl", "days": "soon"}}`
+
+	root, err := p.Explain([]byte(record))
+	assert.Error(t, err)
+
+	expected := &ExplainNode{
+		Name:       "Order",
+		Path:       "$",
+		SchemaType: "record",
+		Found:      true,
+		Children: []*ExplainNode{
+			{Name: "id", Path: "$.id", SchemaType: "long", SourceKey: "order_id", Found: true, RawValue: "1234", Coercions: []CoercionRule{CoercionStringToNumber}},
+			{Name: "coupon", Path: "$.coupon", SchemaType: "union", SourceKey: "coupon", Found: true, RawValue: "SUMMER", Branch: "string"},
+			{Name: "total", Path: "$.total", SchemaType: "union", DefaultUsed: true},
+			{Name: "units", Path: "$.units", SchemaType: "int", DefaultUsed: true},
+			{Name: "user", Path: "$.user", SchemaType: "string", SourceKey: "$.customer.name", Found: true, RawValue: "harry"},
+			{
+				Name:       "shipping",
+				Path:       "$.shipping",
+				SchemaType: "record",
+				SourceKey:  "shipping",
+				Found:      true,
+				Children: []*ExplainNode{
+					{Name: "method", Path: "$.shipping.method", SchemaType: "string", SourceKey: "method", Found: true, RawValue: "owl"},
+					{
+						Name:       "days",
+						Path:       "$.shipping.days",
+						SchemaType: "int",
+						SourceKey:  "days",
+						Found:      true,
+						RawValue:   "soon",
+						Reason:     ReasonInvalidValue,
+						Error:      `parsing string in field "days" error: string "soon" not valid as int`,
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, root)
+
+	text := root.String()
+	assert.Contains(t, text, "Order (record)\n")
+	assert.Contains(t, text, "\n  id (long): key \"order_id\", value \"1234\", coercion string_to_number\n")
+	assert.Contains(t, text, "\n  coupon (union): key \"coupon\", value \"SUMMER\", branch \"string\"\n")
+	assert.Contains(t, text, "\n  total (union): missing, default used\n")
+	assert.Contains(t, text, "\n    days (int): key \"days\", value \"soon\", error invalid_value: ")
+
+	asJSON, err := json.Marshal(root)
+	assert.NoError(t, err)
+
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(asJSON, &decoded))
+	assert.Equal(t, "$", decoded["path"])
+	assert.Len(t, decoded["children"], 6)
+}
+
+func TestExplainCollectsAllErrors(t *testing.T) {
+	p, err := NewParser(explainSchema, WithStrictFields())
+	assert.NoError(t, err)
+
+	root, err := p.ExplainMap(map[string]interface{}{
+		"id":       "1234",
+		"coupon":   nil,
+		"color":    "red",
+		"customer": map[string]interface{}{"name": "harry"},
+	})
+
+	var parseErrs ParseErrors
+	if assert.True(t, errors.As(err, &parseErrs)) {
+		assert.Len(t, parseErrs, 3)
+	}
+
+	assert.Equal(t, ReasonUnknownField, root.Reason)
+	assert.Equal(t, ReasonTypeMismatch, root.Children[0].Reason)
+	assert.Equal(t, "null", root.Children[1].Branch)
+	assert.Equal(t, ReasonMissingValue, root.Children[5].Reason)
+	assert.False(t, root.Children[5].Found)
+
+	root, err = p.Explain([]byte(`{"id": `))
+	assert.Error(t, err)
+	assert.Equal(t, ReasonInvalidJSON, root.Reason)
+}
@@ -0,0 +1,340 @@
+package kedavro
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ouzi-dev/avro-kedavro/pkg/types"
+)
+
+// usedKeys are the keys of the JSON record used by any of the fields, a nil
+// usedKeys doesn't keep track of them
+type usedKeys map[string]struct{}
+
+func (u usedKeys) add(key string) {
+	if u != nil {
+		u[key] = struct{}{}
+	}
+}
+
+// getRecordValues looks for the value of every field of the record in the JSON
+// record and returns them by field name, so the parsers of the fields only need
+// to look for their own name
+func getRecordValues(field *Field, record map[string]interface{}, used usedKeys, state *parseState) (map[string]interface{}, error) {
+	if field.normalizedKeys != nil {
+		return getNormalizedRecordValues(field, record, used, state)
+	}
+
+	values := make(map[string]interface{}, len(field.Fields))
+
+	for _, v := range field.Fields {
+		if v.CatchAll {
+			continue
+		}
+		value, key, ok, err := lookupFieldValue(v, record, used)
+		if err != nil {
+			parseErr := newParseError(v, state.child(v.Name), err)
+			if !isAllErrors(v, state) {
+				return nil, parseErr
+			}
+			// the record parser collects the error when it finds it as the value of the field
+			values[v.Name] = parseErr
+			continue
+		}
+		if !ok {
+			value, key, ok = lookupNestedFieldValue(v, record, used)
+		}
+		if ok {
+			values[v.Name] = value
+			state.explainSourceKey(v.Name, key)
+		}
+	}
+
+	return values, nil
+}
+
+// lookupFieldValue looks for the value of the field in its source path if it has
+// one, or by its name, and then by its aliases in the order they were declared.
+// It returns the key or the source path where the value was found too
+func lookupFieldValue(field *Field, record map[string]interface{}, used usedKeys) (interface{}, string, bool, error) {
+	if field.sourcePath != nil {
+		value, found := getSourceValue(field, record, used)
+		return value, field.Source, found, nil
+	}
+
+	value, found := record[field.Name]
+	if found {
+		used.add(field.Name)
+	}
+	if len(field.Aliases) == 0 || (found && used == nil && field.Opts.AliasConflictPolicy == types.ConflictUseFirst) {
+		return value, field.Name, found, nil
+	}
+
+	foundKey := field.Name
+	for _, alias := range field.Aliases {
+		aliasValue, ok := record[alias]
+		if !ok {
+			continue
+		}
+		used.add(alias)
+
+		if !found {
+			value, found, foundKey = aliasValue, true, alias
+			continue
+		}
+
+		switch field.Opts.AliasConflictPolicy {
+		case types.ConflictUseLast:
+			value, foundKey = aliasValue, alias
+		case types.ConflictError:
+			return nil, "", false, newReasonError(ReasonAmbiguousKey, nil, fmt.Errorf("keys \"%s\" and \"%s\" found for field \"%s\"", foundKey, alias, field.Name))
+		}
+	}
+
+	return value, foundKey, found, nil
+}
+
+// getSourceValue returns the value in the source path of the field, only the
+// first key of the path is marked as used, so strict mode doesn't check the
+// keys of the objects the path goes through
+func getSourceValue(field *Field, record map[string]interface{}, used usedKeys) (interface{}, bool) {
+	if len(field.sourcePath) > 0 && !field.sourcePath[0].isIndex {
+		used.add(field.sourcePath[0].key)
+	}
+
+	return getPathValue(record, field.sourcePath)
+}
+
+// lookupNestedFieldValue looks for a field not found by its name in flattened or
+// nested keys, if the options to do it are enabled. It returns the prefix of the
+// flattened keys or the key of the nested object where the value was found too
+func lookupNestedFieldValue(field *Field, record map[string]interface{}, used usedKeys) (interface{}, string, bool) {
+	if field.sourcePath != nil {
+		return nil, "", false
+	}
+
+	if field.Opts.IsUnflattenKeys && isRecordField(field) {
+		prefix := field.Name + field.Opts.UnflattenSeparator
+		if value, ok := getUnflattenedValue(prefix, record, used); ok {
+			return value, prefix + "*", true
+		}
+	}
+
+	if field.Opts.IsFlattenKeys {
+		value, key, ok := getFlattenedValue(field.Name, field.Opts.FlattenSeparator, record)
+		if ok {
+			used.add(key)
+		}
+		return value, key, ok
+	}
+
+	return nil, "", false
+}
+
+// getUnflattenedValue builds the object of a record from the keys of the JSON
+// record with its prefix, e.g. "address.city" and "address.street" for "address"
+func getUnflattenedValue(prefix string, record map[string]interface{}, used usedKeys) (map[string]interface{}, bool) {
+	var object map[string]interface{}
+	for key, value := range record {
+		if len(key) <= len(prefix) || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if object == nil {
+			object = map[string]interface{}{}
+		}
+		object[key[len(prefix):]] = value
+		used.add(key)
+	}
+
+	return object, object != nil
+}
+
+// getFlattenedValue looks for a flat name in nested objects, e.g. "address_city"
+// in {"address": {"city": "London"}}, trying every separator in the name. It
+// returns the key of the object where the value was found too
+func getFlattenedValue(name, separator string, object map[string]interface{}) (interface{}, string, bool) {
+	if value, ok := object[name]; ok {
+		return value, name, true
+	}
+
+	for i := strings.Index(name, separator); i > 0; {
+		if child, ok := object[name[:i]].(map[string]interface{}); ok {
+			if value, _, ok := getFlattenedValue(name[i+len(separator):], separator, child); ok {
+				return value, name[:i], true
+			}
+		}
+
+		next := strings.Index(name[i+len(separator):], separator)
+		if next < 0 {
+			break
+		}
+		i += len(separator) + next
+	}
+
+	return nil, "", false
+}
+
+// normalizedKey is a field of a record matched by a normalized key, rank is 0
+// for the name of the field and the position of the alias + 1 for aliases
+type normalizedKey struct {
+	field *Field
+	rank  int
+}
+
+// normalizeKey returns the key in lower case without separators, so "userName",
+// "UserName", "user_name" and "user-name" are the same key
+func normalizeKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '_', '-', ' ':
+			return -1
+		default:
+			return unicode.ToLower(r)
+		}
+	}, key)
+}
+
+// getNormalizedKeys indexes the fields of a record by their normalized name and
+// aliases, so we only need to normalize the keys of the JSON record to find them
+func getNormalizedKeys(name string, fields []*Field) (map[string]normalizedKey, error) {
+	keys := map[string]normalizedKey{}
+
+	for _, v := range fields {
+		if v.sourcePath != nil || v.CatchAll {
+			// fields with source path or catch-all fields are not looked up by name
+			continue
+		}
+		names := append([]string{v.Name}, v.Aliases...)
+		for rank, n := range names {
+			key := normalizeKey(n)
+			current, ok := keys[key]
+			if !ok {
+				keys[key] = normalizedKey{field: v, rank: rank}
+				continue
+			}
+			if current.field != v {
+				return nil, fmt.Errorf("fields \"%s\" and \"%s\" in record \"%s\" have the same normalized key \"%s\"", current.field.Name, v.Name, name, key)
+			}
+		}
+	}
+
+	return keys, nil
+}
+
+// getNormalizedRecordValues does the same as getRecordValues but matching the
+// normalized keys of the JSON record with the normalized keys of the fields
+func getNormalizedRecordValues(field *Field, record map[string]interface{}, used usedKeys, state *parseState) (map[string]interface{}, error) {
+	type match struct {
+		key   string
+		rank  int
+		value interface{}
+	}
+
+	matches := make(map[*Field]match, len(field.Fields))
+
+	for key, value := range record {
+		k, ok := field.normalizedKeys[normalizeKey(key)]
+		if !ok {
+			continue
+		}
+		used.add(key)
+
+		current, found := matches[k.field]
+		if !found {
+			matches[k.field] = match{key: key, rank: k.rank, value: value}
+			continue
+		}
+		if _, failed := current.value.(*ParseError); failed {
+			continue
+		}
+
+		keys := []string{current.key, key}
+		sort.Strings(keys)
+		if current.rank == k.rank {
+			err := fmt.Errorf("ambiguous keys \"%s\" and \"%s\" found for field \"%s\"", keys[0], keys[1], k.field.Name)
+			parseErr := newParseError(k.field, state.child(k.field.Name), newReasonError(ReasonAmbiguousKey, nil, err))
+			if !isAllErrors(k.field, state) {
+				return nil, parseErr
+			}
+			matches[k.field] = match{key: key, rank: k.rank, value: parseErr}
+			continue
+		}
+
+		// they are different names of the field, so it's the same as a conflict between aliases
+		switch k.field.Opts.AliasConflictPolicy {
+		case types.ConflictUseFirst:
+			if k.rank < current.rank {
+				matches[k.field] = match{key: key, rank: k.rank, value: value}
+			}
+		case types.ConflictUseLast:
+			if k.rank > current.rank {
+				matches[k.field] = match{key: key, rank: k.rank, value: value}
+			}
+		case types.ConflictError:
+			err := fmt.Errorf("keys \"%s\" and \"%s\" found for field \"%s\"", keys[0], keys[1], k.field.Name)
+			parseErr := newParseError(k.field, state.child(k.field.Name), newReasonError(ReasonAmbiguousKey, nil, err))
+			if !isAllErrors(k.field, state) {
+				return nil, parseErr
+			}
+			matches[k.field] = match{key: key, rank: k.rank, value: parseErr}
+		}
+	}
+
+	values := make(map[string]interface{}, len(field.Fields))
+	for k, v := range matches {
+		values[k.Name] = v.value
+		state.explainSourceKey(k.Name, v.key)
+	}
+
+	for _, v := range field.Fields {
+		if v.CatchAll {
+			continue
+		}
+		if v.sourcePath != nil {
+			if value, ok := getSourceValue(v, record, used); ok {
+				values[v.Name] = value
+				state.explainSourceKey(v.Name, v.Source)
+			}
+			continue
+		}
+		if _, ok := values[v.Name]; ok {
+			continue
+		}
+		if value, key, ok := lookupNestedFieldValue(v, record, used); ok {
+			values[v.Name] = value
+			state.explainSourceKey(v.Name, key)
+		}
+	}
+
+	return values, nil
+}
+
+// checkUnknownKeys fails if the JSON record has keys not used by any field
+func checkUnknownKeys(record map[string]interface{}, used usedKeys, state *parseState) *ParseError {
+	var unknown []string
+	for key := range record {
+		if _, ok := used[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	paths := make([]string, 0, len(unknown))
+	for _, key := range unknown {
+		paths = append(paths, fmt.Sprintf("\"%s.%s\"", state.path(), key))
+	}
+
+	return &ParseError{
+		Path:       state.path(),
+		SchemaType: types.RecordType,
+		Value:      unknown,
+		Reason:     ReasonUnknownField,
+		Err:        fmt.Errorf("unknown fields in \"%s\": %s", state.path(), strings.Join(paths, ", ")),
+	}
+}
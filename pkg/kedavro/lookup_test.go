@@ -0,0 +1,572 @@
+package kedavro
+
+import (
+	"testing"
+
+	"github.com/ouzi-dev/avro-kedavro/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const aliasesSchema = `
+{
+	"name": "Wizard",
+	"type": "record",
+	"fields": [
+		{
+			"name": "user_id",
+			"type": "long",
+			"aliases": ["userId", "id"]
+		},
+		{
+			"name": "house",
+			"type": {
+				"name": "house",
+				"type": "record",
+				"aliases": ["typeAlias"],
+				"fields": [
+					{
+						"name": "name",
+						"type": "string"
+					}
+				]
+			},
+			"aliases": ["school_house"]
+		}
+	]
+}
+`
+
+func TestAliases(t *testing.T) {
+	type testItem struct {
+		policy   types.ConflictPolicy
+		record   string
+		isError  bool
+		expected interface{}
+	}
+
+	house := map[string]interface{}{"name": "slytherin"}
+
+	tests := []testItem{
+		{
+			policy:   types.ConflictUseFirst,
+			record:   `{"user_id": 1, "house": {"name": "slytherin"}}`,
+			expected: map[string]interface{}{"user_id": int64(1), "house": house},
+		},
+		{
+			policy:   types.ConflictUseFirst,
+			record:   `{"userId": 2, "school_house": {"name": "slytherin"}}`,
+			expected: map[string]interface{}{"user_id": int64(2), "house": house},
+		},
+		{
+			policy:   types.ConflictUseFirst,
+			record:   `{"id": 3, "house": {"name": "slytherin"}}`,
+			expected: map[string]interface{}{"user_id": int64(3), "house": house},
+		},
+		{
+			policy:   types.ConflictUseFirst,
+			record:   `{"id": 3, "userId": 2, "user_id": 1, "house": {"name": "slytherin"}}`,
+			expected: map[string]interface{}{"user_id": int64(1), "house": house},
+		},
+		{
+			policy:   types.ConflictUseFirst,
+			record:   `{"id": 3, "userId": 2, "house": {"name": "slytherin"}}`,
+			expected: map[string]interface{}{"user_id": int64(2), "house": house},
+		},
+		{
+			policy:   types.ConflictUseLast,
+			record:   `{"id": 3, "userId": 2, "user_id": 1, "house": {"name": "slytherin"}}`,
+			expected: map[string]interface{}{"user_id": int64(3), "house": house},
+		},
+		{
+			policy:   types.ConflictError,
+			record:   `{"userId": 2, "user_id": 1, "house": {"name": "slytherin"}}`,
+			isError:  true,
+			expected: nil,
+		},
+		{
+			policy:   types.ConflictError,
+			record:   `{"userId": 2, "house": {"name": "slytherin"}}`,
+			expected: map[string]interface{}{"user_id": int64(2), "house": house},
+		},
+		{
+			// aliases of the type are not aliases of the field
+			policy:   types.ConflictUseFirst,
+			record:   `{"user_id": 1, "typeAlias": {"name": "slytherin"}}`,
+			isError:  true,
+			expected: nil,
+		},
+	}
+
+	for _, v := range tests {
+		p, err := NewParser(aliasesSchema, WithAliasConflictPolicy(v.policy))
+		assert.NoError(t, err)
+
+		result, err := p.Parse([]byte(v.record))
+		if v.isError {
+			assert.Error(t, err, v.record)
+		} else {
+			assert.NoError(t, err, v.record)
+		}
+		assert.Equal(t, v.expected, result, v.record)
+	}
+}
+
+func TestInvalidAliases(t *testing.T) {
+	fields := []string{
+		`{"name": "test", "type": "string", "aliases": "test2"}`,
+		`{"name": "test", "type": "string", "aliases": [1234]}`,
+		`{"name": "test", "type": "string", "aliases": [""]}`,
+	}
+
+	for _, v := range fields {
+		_, err := NewParser(`{"name": "Test", "type": "record", "fields": [` + v + `]}`)
+		assert.Error(t, err, v)
+	}
+}
+
+func TestNormalizedKeys(t *testing.T) {
+	schema := `
+	{
+		"name": "Wizard",
+		"type": "record",
+		"fields": [
+			{
+				"name": "user_name",
+				"type": "string"
+			},
+			{
+				"name": "houseName",
+				"type": "string",
+				"aliases": ["school"]
+			},
+			{
+				"name": "wand",
+				"type": "record",
+				"fields": [
+					{
+						"name": "wood_type",
+						"type": "string"
+					}
+				]
+			}
+		]
+	}
+	`
+
+	type testItem struct {
+		record   string
+		isError  bool
+		expected interface{}
+	}
+
+	tests := []testItem{
+		{
+			record: `{"userName": "harry", "house_name": "gryffindor", "Wand": {"WoodType": "holly"}}`,
+			expected: map[string]interface{}{
+				"user_name": "harry",
+				"houseName": "gryffindor",
+				"wand":      map[string]interface{}{"wood_type": "holly"},
+			},
+		},
+		{
+			record: `{"user-name": "harry", "SCHOOL": "gryffindor", "wand": {"wood-type": "holly"}}`,
+			expected: map[string]interface{}{
+				"user_name": "harry",
+				"houseName": "gryffindor",
+				"wand":      map[string]interface{}{"wood_type": "holly"},
+			},
+		},
+		{
+			// the name of the field goes first than the alias
+			record: `{"UserName": "harry", "School": "hufflepuff", "HouseName": "gryffindor", "wand": {"wood_type": "holly"}}`,
+			expected: map[string]interface{}{
+				"user_name": "harry",
+				"houseName": "gryffindor",
+				"wand":      map[string]interface{}{"wood_type": "holly"},
+			},
+		},
+		{
+			record:   `{"userName": "harry", "user_name": "potter", "houseName": "gryffindor", "wand": {"wood_type": "holly"}}`,
+			isError:  true,
+			expected: nil,
+		},
+		{
+			record:   `{"userName": "harry", "houseName": "gryffindor", "wand": {"woodType": "holly", "WoodType": "oak"}}`,
+			isError:  true,
+			expected: nil,
+		},
+	}
+
+	p, err := NewParser(schema, WithNormalizedKeys())
+	assert.NoError(t, err)
+
+	for _, v := range tests {
+		result, err := p.Parse([]byte(v.record))
+		if v.isError {
+			assert.Error(t, err, v.record)
+		} else {
+			assert.NoError(t, err, v.record)
+		}
+		assert.Equal(t, v.expected, result, v.record)
+	}
+
+	// without the option only the exact name matches
+	p, err = NewParser(schema)
+	assert.NoError(t, err)
+
+	_, err = p.Parse([]byte(tests[0].record))
+	assert.Error(t, err)
+}
+
+func TestNormalizedKeysConflicts(t *testing.T) {
+	schema := `
+	{
+		"name": "Wizard",
+		"type": "record",
+		"fields": [
+			{
+				"name": "user_name",
+				"type": "string"
+			},
+			{
+				"name": "userName",
+				"type": "string"
+			}
+		]
+	}
+	`
+
+	_, err := NewParser(schema)
+	assert.NoError(t, err)
+
+	_, err = NewParser(schema, WithNormalizedKeys())
+	assert.Error(t, err)
+
+	schema = `
+	{
+		"name": "Wizard",
+		"type": "record",
+		"fields": [
+			{
+				"name": "user_name",
+				"type": "string",
+				"aliases": ["userName", "name"]
+			}
+		]
+	}
+	`
+
+	p, err := NewParser(schema, WithNormalizedKeys(), WithAliasConflictPolicy(types.ConflictError))
+	assert.NoError(t, err)
+
+	_, err = p.Parse([]byte(`{"UserName": "harry", "Name": "potter"}`))
+	assert.Error(t, err)
+
+	p, err = NewParser(schema, WithNormalizedKeys(), WithAliasConflictPolicy(types.ConflictUseLast))
+	assert.NoError(t, err)
+
+	result, err := p.Parse([]byte(`{"UserName": "harry", "Name": "potter"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"user_name": "potter"}, result)
+}
+
+func TestUnflattenKeys(t *testing.T) {
+	schema := `
+	{
+		"name": "Wizard",
+		"type": "record",
+		"fields": [
+			{
+				"name": "name",
+				"type": "string"
+			},
+			{
+				"name": "address",
+				"type": "record",
+				"fields": [
+					{
+						"name": "city",
+						"type": "string"
+					},
+					{
+						"name": "geo",
+						"type": "record",
+						"fields": [
+							{
+								"name": "lat",
+								"type": "double"
+							}
+						]
+					}
+				]
+			}
+		]
+	}
+	`
+
+	record := `{"name": "harry", "address.city": "London", "address.geo.lat": 51.5}`
+
+	expected := map[string]interface{}{
+		"name": "harry",
+		"address": map[string]interface{}{
+			"city": "London",
+			"geo":  map[string]interface{}{"lat": 51.5},
+		},
+	}
+
+	p, err := NewParser(schema, WithUnflattenKeys("."))
+	assert.NoError(t, err)
+
+	result, err := p.Parse([]byte(record))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+
+	// nested objects still work
+	result, err = p.Parse([]byte(`{"name": "harry", "address": {"city": "London", "geo.lat": 51.5}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+
+	p, err = NewParser(schema)
+	assert.NoError(t, err)
+
+	_, err = p.Parse([]byte(record))
+	assert.Error(t, err)
+
+	_, err = NewParser(schema, WithUnflattenKeys(""))
+	assert.Error(t, err)
+}
+
+func TestFlattenKeys(t *testing.T) {
+	schema := `
+	{
+		"name": "Wizard",
+		"type": "record",
+		"fields": [
+			{
+				"name": "user_name",
+				"type": "string"
+			},
+			{
+				"name": "address_city",
+				"type": "string"
+			},
+			{
+				"name": "address_geo_lat",
+				"type": ["null", "double"],
+				"default": null
+			},
+			{
+				"name": "wand_core_material",
+				"type": "string"
+			}
+		]
+	}
+	`
+
+	p, err := NewParser(schema, WithFlattenKeys("_"))
+	assert.NoError(t, err)
+
+	result, err := p.Parse([]byte(`
+	{
+		"user_name": "harry",
+		"address": {"city": "London", "geo": {"lat": 51.5}},
+		"wand_core": {"material": "phoenix feather"}
+	}`))
+	assert.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"user_name":          "harry",
+		"address_city":       "London",
+		"address_geo_lat":    map[string]interface{}{"double": 51.5},
+		"wand_core_material": "phoenix feather",
+	}
+	assert.Equal(t, expected, result)
+
+	_, err = p.Parse([]byte(`{"user": {"name": "harry"}, "address": {"city": "London"}, "wand": {"core": "phoenix"}}`))
+	assert.Error(t, err)
+
+	p, err = NewParser(schema)
+	assert.NoError(t, err)
+
+	_, err = p.Parse([]byte(`{"user_name": "harry", "address": {"city": "London"}, "wand_core_material": "phoenix"}`))
+	assert.Error(t, err)
+}
+
+func TestStrictFields(t *testing.T) {
+	schema := `
+	{
+		"name": "Wizard",
+		"type": "record",
+		"fields": [
+			{
+				"name": "user_id",
+				"type": "long",
+				"aliases": ["userId"]
+			},
+			{
+				"name": "house",
+				"type": "string",
+				"kedavro.source": "$.school.house"
+			},
+			{
+				"name": "wand",
+				"type": "record",
+				"fields": [
+					{
+						"name": "wood",
+						"type": "string"
+					}
+				]
+			}
+		]
+	}
+	`
+
+	type testItem struct {
+		record  string
+		isError bool
+		paths   []string
+	}
+
+	tests := []testItem{
+		{
+			record: `{"user_id": 1, "school": {"house": "gryffindor", "points": 10}, "wand": {"wood": "holly"}}`,
+		},
+		{
+			record: `{"user_id": 1, "userId": 1, "school": {"house": "gryffindor"}, "wand": {"wood": "holly"}}`,
+		},
+		{
+			record:  `{"user_id": 1, "name": "harry", "age": 17, "school": {"house": "gryffindor"}, "wand": {"wood": "holly"}}`,
+			isError: true,
+			paths:   []string{"$.name", "$.age"},
+		},
+		{
+			record:  `{"user_id": 1, "school": {"house": "gryffindor"}, "wand": {"wood": "holly", "core": "phoenix"}}`,
+			isError: true,
+			paths:   []string{"$.wand.core"},
+		},
+	}
+
+	p, err := NewParser(schema, WithStrictFields())
+	assert.NoError(t, err)
+
+	for _, v := range tests {
+		_, err := p.Parse([]byte(v.record))
+		if !v.isError {
+			assert.NoError(t, err, v.record)
+			continue
+		}
+		if assert.Error(t, err, v.record) {
+			for _, path := range v.paths {
+				assert.Contains(t, err.Error(), "\""+path+"\"", v.record)
+			}
+		}
+	}
+
+	// without strict mode unknown fields are ignored
+	p, err = NewParser(schema)
+	assert.NoError(t, err)
+
+	_, err = p.Parse([]byte(tests[2].record))
+	assert.NoError(t, err)
+}
+
+func TestStrictFieldsWithNestedKeys(t *testing.T) {
+	schema := `
+	{
+		"name": "Wizard",
+		"type": "record",
+		"fields": [
+			{
+				"name": "user_name",
+				"type": "string"
+			},
+			{
+				"name": "address",
+				"type": "record",
+				"fields": [
+					{
+						"name": "city",
+						"type": "string"
+					}
+				]
+			}
+		]
+	}
+	`
+
+	p, err := NewParser(schema, WithStrictFields(), WithNormalizedKeys(), WithUnflattenKeys("."), WithFlattenKeys("_"))
+	assert.NoError(t, err)
+
+	_, err = p.Parse([]byte(`{"UserName": "harry", "address.city": "London"}`))
+	assert.NoError(t, err)
+
+	_, err = p.Parse([]byte(`{"user": {"name": "harry"}, "address": {"city": "London"}}`))
+	assert.NoError(t, err)
+
+	_, err = p.Parse([]byte(`{"user": {"name": "harry"}, "address.city": "London", "address.street": "privet drive"}`))
+	assert.Error(t, err)
+}
+
+func TestStrictFieldsWithSourcePath(t *testing.T) {
+	schema := `
+	{
+		"name": "Event",
+		"type": "record",
+		"fields": [
+			{
+				"name": "user_id",
+				"type": "long",
+				"kedavro.source": "$.payload.user.id"
+			},
+			{
+				"name": "first_item",
+				"type": "string",
+				"kedavro.source": "$['items'][0]"
+			}
+		]
+	}
+	`
+
+	type testItem struct {
+		record  string
+		isError bool
+		paths   []string
+	}
+
+	tests := []testItem{
+		{
+			record: `{"payload": {"user": {"id": 1}}, "items": ["wand"]}`,
+		},
+		{
+			// keys of the objects in the source path are not checked
+			record: `{"payload": {"user": {"id": 1, "name": "harry"}, "ts": 1}, "items": ["wand", "broom"]}`,
+		},
+		{
+			record:  `{"payload": {"user": {"id": 1}}, "items": ["wand"], "user": {"id": 2}}`,
+			isError: true,
+			paths:   []string{"$.user"},
+		},
+		{
+			record:  `{"payload": {"user": {"id": 1}}, "items": ["wand"], "id": 2, "ts": 1}`,
+			isError: true,
+			paths:   []string{"$.id", "$.ts"},
+		},
+	}
+
+	p, err := NewParser(schema, WithStrictFields())
+	assert.NoError(t, err)
+
+	for _, v := range tests {
+		_, err := p.Parse([]byte(v.record))
+		if !v.isError {
+			assert.NoError(t, err, v.record)
+			continue
+		}
+		if assert.Error(t, err, v.record) {
+			for _, path := range v.paths {
+				assert.Contains(t, err.Error(), "\""+path+"\"", v.record)
+			}
+		}
+	}
+}
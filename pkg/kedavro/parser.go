@@ -8,12 +8,29 @@ import (
 )
 
 type parser struct {
-	schema *Field
+	schema         *Field
+	schemaString   string
+	fingerprint    uint64
+	fingerprintErr error
 }
 
 type Parser interface {
 	Parse(record []byte) (interface{}, error)
 	ParseMap(record map[string]interface{}) (interface{}, error)
+	ParseWithReport(record []byte) (interface{}, *Report, error)
+	ParseMapWithReport(record map[string]interface{}) (interface{}, *Report, error)
+	ParseToBinary(buf []byte, record []byte) ([]byte, error)
+	ParseMapToBinary(buf []byte, record map[string]interface{}) ([]byte, error)
+	ParseToTextual(buf []byte, record []byte) ([]byte, error)
+	ParseMapToTextual(buf []byte, record map[string]interface{}) ([]byte, error)
+	Explain(record []byte) (*ExplainNode, error)
+	ExplainMap(record map[string]interface{}) (*ExplainNode, error)
+	ParseToConfluent(buf []byte, record []byte) ([]byte, error)
+	ParseMapToConfluent(buf []byte, record map[string]interface{}) ([]byte, error)
+	ParseToSingleObject(buf []byte, record []byte) ([]byte, error)
+	ParseMapToSingleObject(buf []byte, record map[string]interface{}) ([]byte, error)
+	Schema() string
+	Fingerprint() (uint64, error)
 }
 
 // ParserOption reconfigure the parser creation.
@@ -48,6 +65,69 @@ func WithNowForNullTimestamp() ParserOption {
 	}
 }
 
+func WithRecordDefaultFromFields() ParserOption {
+	return func(o *types.Options) {
+		o.IsRecordDefaultFromFields = true
+	}
+}
+
+func WithAliasConflictPolicy(policy types.ConflictPolicy) ParserOption {
+	return func(o *types.Options) {
+		o.AliasConflictPolicy = policy
+	}
+}
+
+func WithNormalizedKeys() ParserOption {
+	return func(o *types.Options) {
+		o.IsNormalizeKeys = true
+	}
+}
+
+func WithUnflattenKeys(separator string) ParserOption {
+	return func(o *types.Options) {
+		o.IsUnflattenKeys = true
+		o.UnflattenSeparator = separator
+	}
+}
+
+func WithFlattenKeys(separator string) ParserOption {
+	return func(o *types.Options) {
+		o.IsFlattenKeys = true
+		o.FlattenSeparator = separator
+	}
+}
+
+func WithStrictFields() ParserOption {
+	return func(o *types.Options) {
+		o.IsStrictFields = true
+	}
+}
+
+func WithEmbeddedJSON() ParserOption {
+	return func(o *types.Options) {
+		o.IsEmbeddedJSON = true
+	}
+}
+
+func WithAllErrors() ParserOption {
+	return func(o *types.Options) {
+		o.IsAllErrors = true
+	}
+}
+
+func WithErrorPolicy(policy types.ErrorPolicy) ParserOption {
+	return func(o *types.Options) {
+		o.ErrorPolicy = policy
+	}
+}
+
+func WithSchemaID(id uint32) ParserOption {
+	return func(o *types.Options) {
+		o.IsSchemaID = true
+		o.SchemaID = id
+	}
+}
+
 func NewParser(schemaString string, opts ...ParserOption) (Parser, error) {
 	s := map[string]interface{}{}
 
@@ -61,6 +141,10 @@ func NewParser(schemaString string, opts ...ParserOption) (Parser, error) {
 		opt(&options)
 	}
 
+	if (options.IsUnflattenKeys && len(options.UnflattenSeparator) == 0) || (options.IsFlattenKeys && len(options.FlattenSeparator) == 0) {
+		return nil, fmt.Errorf("separator of flattened keys can't be empty")
+	}
+
 	rootField, err := ParseSchemaField(s, options)
 	if err != nil {
 		return nil, err
@@ -70,22 +154,125 @@ func NewParser(schemaString string, opts ...ParserOption) (Parser, error) {
 		return nil, fmt.Errorf("schema root field must be of type record")
 	}
 
+	if err := setDefaultValues(rootField, "$"); err != nil {
+		return nil, err
+	}
+
 	parser := &parser{
-		schema: rootField,
+		schema:       rootField,
+		schemaString: schemaString,
 	}
 
+	// the fingerprint is only needed by the single object encoding, so a schema
+	// without canonical form, like nested records without name, can still be used
+	parser.fingerprint, parser.fingerprintErr = FingerprintCRC64(schemaString)
+
 	return parser, nil
 }
 
 func (p *parser) Parse(record []byte) (interface{}, error) {
-	jsonRecord := map[string]interface{}{}
-	if err := json.Unmarshal(record, &jsonRecord); err != nil {
-		return nil, fmt.Errorf("unmarshall record failed: %v", err)
+	jsonRecord, err := unmarshalRecord(record)
+	if err != nil {
+		return nil, err
 	}
 
-	return parseRecord(p.schema, jsonRecord)
+	return parseRecord(p.schema, jsonRecord, nil)
 }
 
 func (p *parser) ParseMap(record map[string]interface{}) (interface{}, error) {
-	return parseRecord(p.schema, record)
+	return parseRecord(p.schema, record, nil)
+}
+
+func (p *parser) ParseWithReport(record []byte) (interface{}, *Report, error) {
+	jsonRecord, err := unmarshalRecord(record)
+	if err != nil {
+		return nil, &Report{}, err
+	}
+
+	return p.ParseMapWithReport(jsonRecord)
+}
+
+func (p *parser) ParseMapWithReport(record map[string]interface{}) (interface{}, *Report, error) {
+	report := &Report{}
+	result, err := parseRecord(p.schema, record, &parseState{report: report})
+
+	return result, report, err
+}
+
+func (p *parser) ParseToBinary(buf []byte, record []byte) ([]byte, error) {
+	jsonRecord, err := unmarshalRecord(record)
+	if err != nil {
+		return buf, err
+	}
+
+	return p.ParseMapToBinary(buf, jsonRecord)
+}
+
+func (p *parser) ParseMapToBinary(buf []byte, record map[string]interface{}) ([]byte, error) {
+	start := len(buf)
+	if _, err := parseRecord(p.schema, record, &parseState{buf: &buf}); err != nil {
+		return buf[:start], err
+	}
+
+	return buf, nil
+}
+
+func (p *parser) ParseToTextual(buf []byte, record []byte) ([]byte, error) {
+	jsonRecord, err := unmarshalRecord(record)
+	if err != nil {
+		return buf, err
+	}
+
+	return p.ParseMapToTextual(buf, jsonRecord)
+}
+
+func (p *parser) ParseMapToTextual(buf []byte, record map[string]interface{}) ([]byte, error) {
+	native, err := parseRecord(p.schema, record, nil)
+	if err != nil {
+		return buf, err
+	}
+
+	result, err := appendTextual(buf, p.schema, native)
+	if err != nil {
+		return buf, err
+	}
+
+	return result, nil
+}
+
+func (p *parser) Explain(record []byte) (*ExplainNode, error) {
+	jsonRecord, err := unmarshalRecord(record)
+	if err != nil {
+		root := newExplainRoot(p.schema)
+		(&parseState{node: root}).explainError(err.(*ParseError))
+		return root, err
+	}
+
+	return p.ExplainMap(jsonRecord)
+}
+
+func (p *parser) ExplainMap(record map[string]interface{}) (*ExplainNode, error) {
+	// explaining a record collects all the errors, so we can see all of them in the tree
+	root := newExplainRoot(p.schema)
+	_, err := parseRecord(p.schema, record, &parseState{node: root})
+
+	return root, err
+}
+
+func (p *parser) Schema() string {
+	return p.schemaString
+}
+
+func unmarshalRecord(record []byte) (map[string]interface{}, error) {
+	jsonRecord := map[string]interface{}{}
+	if err := json.Unmarshal(record, &jsonRecord); err != nil {
+		return nil, &ParseError{
+			Path:       "$",
+			SchemaType: types.RecordType,
+			Reason:     ReasonInvalidJSON,
+			Err:        fmt.Errorf("unmarshall record failed: %v", err),
+		}
+	}
+
+	return jsonRecord, nil
 }
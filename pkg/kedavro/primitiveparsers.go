@@ -8,153 +8,232 @@ import (
 	"github.com/ouzi-dev/avro-kedavro/pkg/types"
 )
 
-type valueParserFunction func(field *Field, value interface{}) (interface{}, error)
+type valueParserFunction func(field *Field, value interface{}, state *parseState) (interface{}, error)
 
-func parseRecord(field *Field, record map[string]interface{}) (interface{}, error) {
+func parseRecord(field *Field, record map[string]interface{}, state *parseState) (interface{}, error) {
 	avroRecord := map[string]interface{}{}
+	var errs ParseErrors
+
+	var used usedKeys
+	if field.Opts.IsStrictFields || field.catchAllField != nil {
+		used = usedKeys{}
+	}
+
+	values, err := getRecordValues(field, record, used, state)
+	if err != nil {
+		return nil, err
+	}
+
+	if field.catchAllField != nil {
+		// the catch-all field gets the unknown keys, so there aren't unknown keys for strict mode
+		catchAllValue, err := getCatchAllValue(record, used)
+		if err != nil {
+			parseErr := newParseError(field.catchAllField, state.child(field.catchAllField.Name), err)
+			if !isAllErrors(field, state) {
+				return nil, parseErr
+			}
+			values[field.catchAllField.Name] = parseErr
+		} else {
+			values[field.catchAllField.Name] = catchAllValue
+		}
+	} else if field.Opts.IsStrictFields {
+		if err := checkUnknownKeys(record, used, state); err != nil {
+			state.explainError(err)
+			if !isAllErrors(field, state) {
+				return nil, err
+			}
+			errs = append(errs, err)
+		}
+	}
 
 	for _, v := range field.Fields {
-		newField, err := parseField(v, record)
+		fieldState := state.child(v.Name)
+		fieldState.explainField(v, values)
+		if parseErr, ok := values[v.Name].(*ParseError); ok {
+			// we couldn't get the value of the field, only when collecting all the errors
+			fieldState.explainError(parseErr)
+			errs = append(errs, parseErr)
+			continue
+		}
+
+		start := fieldState.encodedLen()
+		report := fieldState.scratch()
+		newField, err := parseField(v, values, fieldState)
+		// the coercions of the field are dropped if the error policy replaces its value
+		fieldState.mergeScratch(report, err == nil || !isReplacedByErrorPolicy(v, err))
+		replaced := false
 		if err != nil {
-			return nil, fmt.Errorf("field parse error, field: %v, error: %v", v, err)
+			newField, err = applyErrorPolicy(v, fieldState, newField, err)
+			replaced = err == nil
+		}
+		if err == nil {
+			err = fieldState.encodeField(v, newField, start, replaced)
+		}
+		fieldState.explainResult(v, err)
+		if err != nil {
+			if !isAllErrors(field, state) {
+				return nil, newParseError(v, fieldState, err)
+			}
+			errs = appendParseErrors(errs, v, fieldState, err)
+			// nested records return what they could parse
+			if newField != nil {
+				avroRecord[v.Name] = newField
+			}
+			continue
 		}
 
-		avroRecord[v.Name] = newField
+		if !state.isEncoding() {
+			avroRecord[v.Name] = newField
+		}
+	}
+
+	if len(errs) > 0 {
+		return avroRecord, errs
+	}
+
+	if state.isEncoding() {
+		// the fields are already in the binary encoding
+		return encodedRecord{}, nil
 	}
 
 	return avroRecord, nil
 }
 
-func parseField(field *Field, record map[string]interface{}) (interface{}, error) {
+func parseField(field *Field, record map[string]interface{}, state *parseState) (interface{}, error) {
 	var result interface{}
 	var err error
 	switch field.Type {
 	case types.Primitive:
-		result, err = parsePrimitiveField(field, record)
+		result, err = parsePrimitiveField(field, record, state)
 	case types.Union:
 		// Union
-		result, err = parseUnionField(field, record)
+		result, err = parseUnionField(field, record, state)
 	default:
 		err = fmt.Errorf("unknown field type in field %s", field.Name)
 	}
 
 	if err != nil {
-		return nil, err
+		// records can return what they could parse when collecting all the errors
+		return result, err
 	}
 
 	return result, nil
 }
 
-func parsePrimitiveField(field *Field, record map[string]interface{}) (interface{}, error) {
-	return field.ParseField(field, record)
+func parsePrimitiveField(field *Field, record map[string]interface{}, state *parseState) (interface{}, error) {
+	return field.ParseField(field, record, state)
 }
 
-func parseStringField(field *Field, record map[string]interface{}) (interface{}, error) {
-	return parseWithDefaultValue(field, record, parseStringValue)
+func parseStringField(field *Field, record map[string]interface{}, state *parseState) (interface{}, error) {
+	return parseWithDefaultValue(field, record, state, parseStringValue)
 }
 
-func parseStringValue(field *Field, value interface{}) (interface{}, error) {
+func parseStringValue(field *Field, value interface{}, state *parseState) (interface{}, error) {
 	v, ok := value.(string)
 
 	if !ok {
-		return nil, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"string\"", value, field.Name)
+		return nil, newReasonError(ReasonTypeMismatch, value, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"string\"", value, field.Name))
 	}
 
 	return v, nil
 }
 
-func parseBoolValue(field *Field, value interface{}) (interface{}, error) {
+func parseBoolValue(field *Field, value interface{}, state *parseState) (interface{}, error) {
 	v, ok := value.(bool)
 
 	if !ok {
-		if field.Opts.IsStringToBool {
+		if _, isString := value.(string); isString && field.Opts.IsStringToBool {
 			f, err := getStringAs(value, types.BoolType)
 			if err != nil {
-				return nil, fmt.Errorf("parsing string in field \"%s\" error: %v", field.Name, err)
+				return nil, newReasonError(ReasonInvalidValue, value, fmt.Errorf("parsing string in field \"%s\" error: %v", field.Name, err))
 			}
+			state.addCoercion(CoercionStringToBool, value, f)
 			return f, nil
 		}
-		return nil, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"boolean\"", value, field.Name)
+		return nil, newReasonError(ReasonTypeMismatch, value, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"boolean\"", value, field.Name))
 	}
 
 	return v, nil
 }
 
-func parseBoolField(field *Field, record map[string]interface{}) (interface{}, error) {
-	return parseWithDefaultValue(field, record, parseBoolValue)
+func parseBoolField(field *Field, record map[string]interface{}, state *parseState) (interface{}, error) {
+	return parseWithDefaultValue(field, record, state, parseBoolValue)
 }
 
-func parseBytesValue(field *Field, value interface{}) (interface{}, error) {
+func parseBytesValue(field *Field, value interface{}, state *parseState) (interface{}, error) {
 	// []byte is a string in the json, we need to return it as []byte
 	v, ok := value.(string)
 
 	if !ok {
-		return nil, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"bytes\"", value, field.Name)
+		return nil, newReasonError(ReasonTypeMismatch, value, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"bytes\"", value, field.Name))
 	}
 
 	return []byte(v), nil
 }
 
-func parseBytesField(field *Field, record map[string]interface{}) (interface{}, error) {
-	return parseWithDefaultValue(field, record, parseBytesValue)
+func parseBytesField(field *Field, record map[string]interface{}, state *parseState) (interface{}, error) {
+	return parseWithDefaultValue(field, record, state, parseBytesValue)
 }
 
-func parseFloatValue(field *Field, value interface{}) (interface{}, error) {
+func parseFloatValue(field *Field, value interface{}, state *parseState) (interface{}, error) {
 	v, ok := value.(float64)
 
 	if !ok {
-		if field.Opts.IsStringToNumber {
+		if _, isString := value.(string); isString && field.Opts.IsStringToNumber {
 			f, err := getStringAs(value, types.FloatType)
 			if err != nil {
-				return nil, fmt.Errorf("parsing string in field \"%s\" error: %v", field.Name, err)
+				return nil, newReasonError(ReasonInvalidValue, value, fmt.Errorf("parsing string in field \"%s\" error: %v", field.Name, err))
 			}
+			state.addCoercion(CoercionStringToNumber, value, f)
 			return f, nil
 		}
-		return nil, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"float\"", value, field.Name)
+		return nil, newReasonError(ReasonTypeMismatch, value, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"float\"", value, field.Name))
 	}
 
 	return float32(v), nil
 }
 
-func parseFloatField(field *Field, record map[string]interface{}) (interface{}, error) {
-	return parseWithDefaultValue(field, record, parseFloatValue)
+func parseFloatField(field *Field, record map[string]interface{}, state *parseState) (interface{}, error) {
+	return parseWithDefaultValue(field, record, state, parseFloatValue)
 }
 
-func parseDoubleValue(field *Field, value interface{}) (interface{}, error) {
+func parseDoubleValue(field *Field, value interface{}, state *parseState) (interface{}, error) {
 	v, ok := value.(float64)
 
 	if !ok {
-		if field.Opts.IsStringToNumber {
+		if _, isString := value.(string); isString && field.Opts.IsStringToNumber {
 			f, err := getStringAs(value, types.DoubleType)
 			if err != nil {
-				return nil, fmt.Errorf("parsing string in field \"%s\" error: %v", field.Name, err)
+				return nil, newReasonError(ReasonInvalidValue, value, fmt.Errorf("parsing string in field \"%s\" error: %v", field.Name, err))
 			}
+			state.addCoercion(CoercionStringToNumber, value, f)
 			return f, nil
 		}
-		return nil, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"double\"", value, field.Name)
+		return nil, newReasonError(ReasonTypeMismatch, value, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"double\"", value, field.Name))
 	}
 
 	return v, nil
 }
 
-func parseDoubleField(field *Field, record map[string]interface{}) (interface{}, error) {
-	return parseWithDefaultValue(field, record, parseDoubleValue)
+func parseDoubleField(field *Field, record map[string]interface{}, state *parseState) (interface{}, error) {
+	return parseWithDefaultValue(field, record, state, parseDoubleValue)
 }
 
-func parseLongValueAsNumber(field *Field, value interface{}) (interface{}, error) {
+func parseLongValueAsNumber(field *Field, value interface{}, state *parseState) (interface{}, error) {
 	var result int64
 
 	v, ok := value.(float64)
 
 	if !ok {
-		if field.Opts.IsStringToNumber {
+		if _, isString := value.(string); isString && field.Opts.IsStringToNumber {
 			f, err := getStringAs(value, types.LongType)
 			if err != nil {
-				return nil, fmt.Errorf("parsing string in field \"%s\" error: %v", field.Name, err)
+				return nil, newReasonError(ReasonInvalidValue, value, fmt.Errorf("parsing string in field \"%s\" error: %v", field.Name, err))
 			}
 			result = f.(int64)
+			state.addCoercion(CoercionStringToNumber, value, result)
 		} else {
-			return nil, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"long\"", value, field.Name)
+			return nil, newReasonError(ReasonTypeMismatch, value, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"long\"", value, field.Name))
 		}
 	} else {
 		result = int64(v)
@@ -163,14 +242,14 @@ func parseLongValueAsNumber(field *Field, value interface{}) (interface{}, error
 	return result, nil
 }
 
-func parseLongValueAsTimestamp(field *Field, value interface{}) (interface{}, error) {
+func parseLongValueAsTimestamp(field *Field, value interface{}, state *parseState) (interface{}, error) {
 	// so timestamp is a bit different... we need to try first, and if we get an error we need to check if we need to format the date
-	v, err := parseLongValueAsNumber(field, value)
+	v, err := parseLongValueAsNumber(field, value, state)
 
 	if err != nil {
 		// special case, if we get a timestamp as number with decimals but it's a string...
 		// it will fail parsing to long, but we can deal with it as a double
-		d, err := parseDoubleValue(field, value)
+		d, err := parseDoubleValue(field, value, state)
 		if err == nil {
 			asFloat := d.(float64)
 			sec, dec := math.Modf(asFloat)
@@ -198,12 +277,13 @@ func parseLongValueAsTimestamp(field *Field, value interface{}) (interface{}, er
 			f, ok := value.(string)
 			if !ok {
 				// we can't parse as number and it's not a string so... error
-				return nil, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"long\", \"double\" or \"string\"", value, field.Name)
+				return nil, newReasonError(ReasonTypeMismatch, value, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"long\", \"double\" or \"string\"", value, field.Name))
 			}
 			t, err := time.Parse(field.Opts.DateTimeFormat, f)
 			if err != nil {
-				return nil, fmt.Errorf("error while parsing value \"%v\" in field \"%s\" as date with format \"%s\"", value, field.Name, field.Opts.DateTimeFormat)
+				return nil, newReasonError(ReasonInvalidValue, value, fmt.Errorf("error while parsing value \"%v\" in field \"%s\" as date with format \"%s\"", value, field.Name, field.Opts.DateTimeFormat))
 			}
+			state.addCoercion(CoercionDateTimeFormat, value, t)
 			return t, nil
 		}
 		return nil, err
@@ -213,7 +293,9 @@ func parseLongValueAsTimestamp(field *Field, value interface{}) (interface{}, er
 
 	// now we have to parse the long to a time.Time, if we have any of the flags on it's easy
 	if field.Opts.IsTimestampToMillis || field.Opts.IsTimestampToMicros {
-		return time.Unix(result, 0), nil
+		t := time.Unix(result, 0)
+		state.addCoercion(CoercionSecondsToTimestamp, result, t)
+		return t, nil
 	}
 
 	if field.LogicalType == types.TimestampMillis {
@@ -222,63 +304,69 @@ func parseLongValueAsTimestamp(field *Field, value interface{}) (interface{}, er
 	return time.Unix(0, result*int64(time.Microsecond)), nil
 }
 
-func parseLongValue(field *Field, value interface{}) (interface{}, error) {
+func parseLongValue(field *Field, value interface{}, state *parseState) (interface{}, error) {
 	if field.LogicalType == types.TimestampMillis || field.LogicalType == types.TimestampMicros {
-		return parseLongValueAsTimestamp(field, value)
+		return parseLongValueAsTimestamp(field, value, state)
 	}
-	return parseLongValueAsNumber(field, value)
+	return parseLongValueAsNumber(field, value, state)
 }
 
-func parseLongField(field *Field, record map[string]interface{}) (interface{}, error) {
+func parseLongField(field *Field, record map[string]interface{}, state *parseState) (interface{}, error) {
 	if field.LogicalType == types.TimestampMillis || field.LogicalType == types.TimestampMicros {
 		if v, ok := record[field.Name]; (!ok || v == nil) && field.Opts.IsSetNowForNilTimestamp {
-			return time.Now(), nil
+			now := time.Now()
+			state.addCoercion(CoercionNowForNull, nil, now)
+			return now, nil
 		}
 	}
-	return parseWithDefaultValue(field, record, parseLongValue)
+	return parseWithDefaultValue(field, record, state, parseLongValue)
 }
 
-func parseIntValue(field *Field, value interface{}) (interface{}, error) {
+func parseIntValue(field *Field, value interface{}, state *parseState) (interface{}, error) {
 	v, ok := value.(float64)
 
 	if !ok {
-		if field.Opts.IsStringToNumber {
+		if _, isString := value.(string); isString && field.Opts.IsStringToNumber {
 			f, err := getStringAs(value, types.IntType)
 			if err != nil {
-				return nil, fmt.Errorf("parsing string in field \"%s\" error: %v", field.Name, err)
+				return nil, newReasonError(ReasonInvalidValue, value, fmt.Errorf("parsing string in field \"%s\" error: %v", field.Name, err))
 			}
+			state.addCoercion(CoercionStringToNumber, value, f)
 			return f, nil
 		}
-		return nil, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"int\"", value, field.Name)
+		return nil, newReasonError(ReasonTypeMismatch, value, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"int\"", value, field.Name))
 	}
 
 	return int32(v), nil
 }
 
-func parseIntField(field *Field, record map[string]interface{}) (interface{}, error) {
-	return parseWithDefaultValue(field, record, parseIntValue)
+func parseIntField(field *Field, record map[string]interface{}, state *parseState) (interface{}, error) {
+	return parseWithDefaultValue(field, record, state, parseIntValue)
 }
 
-func parseNilField(field *Field, record map[string]interface{}) (interface{}, error) {
-	return parseWithDefaultValue(field, record, parseNilValue)
+func parseNilField(field *Field, record map[string]interface{}, state *parseState) (interface{}, error) {
+	return parseWithDefaultValue(field, record, state, parseNilValue)
 }
 
-func parseNilValue(field *Field, value interface{}) (interface{}, error) {
+func parseNilValue(field *Field, value interface{}, state *parseState) (interface{}, error) {
 	if value != nil {
-		return nil, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"null\"", value, field.Name)
+		return nil, newReasonError(ReasonTypeMismatch, value, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"null\"", value, field.Name))
 	}
 
 	return nil, nil
 }
 
-func parseWithDefaultValue(field *Field, record map[string]interface{}, valueParser valueParserFunction) (interface{}, error) {
+func parseWithDefaultValue(field *Field, record map[string]interface{}, state *parseState, valueParser valueParserFunction) (interface{}, error) {
 	value, ok := record[field.Name]
 	if !ok {
 		if !field.HasDefault {
-			return nil, fmt.Errorf("value for field \"%s\" not found", field.Name)
+			return nil, newReasonError(ReasonMissingValue, nil, fmt.Errorf("value for field \"%s\" not found", field.Name))
+		}
+		if field.HasNativeDefault {
+			return copyNativeValue(field.NativeDefault), nil
 		}
 		value = field.DefaultValue
 	}
 
-	return valueParser(field, value)
+	return valueParser(field, value, state)
 }
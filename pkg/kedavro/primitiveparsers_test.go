@@ -75,7 +75,7 @@ func TestNilPrimitiveType(t *testing.T) {
 	}
 
 	for _, v := range tests {
-		result, err := parseNilField(v.field, v.record)
+		result, err := parseNilField(v.field, v.record, nil)
 		assert.Equal(t, v.expected, result)
 		if v.isError {
 			assert.Error(t, err)
@@ -292,19 +292,19 @@ func testPrimitiveField(t *testing.T, test testType) {
 
 		switch test.fieldType {
 		case types.StringType:
-			result, err = parseStringField(v.field, v.record)
+			result, err = parseStringField(v.field, v.record, nil)
 		case types.BoolType:
-			result, err = parseBoolField(v.field, v.record)
+			result, err = parseBoolField(v.field, v.record, nil)
 		case types.BytesType:
-			result, err = parseBytesField(v.field, v.record)
+			result, err = parseBytesField(v.field, v.record, nil)
 		case types.FloatType:
-			result, err = parseFloatField(v.field, v.record)
+			result, err = parseFloatField(v.field, v.record, nil)
 		case types.DoubleType:
-			result, err = parseDoubleField(v.field, v.record)
+			result, err = parseDoubleField(v.field, v.record, nil)
 		case types.LongType:
-			result, err = parseLongField(v.field, v.record)
+			result, err = parseLongField(v.field, v.record, nil)
 		case types.IntType:
-			result, err = parseIntField(v.field, v.record)
+			result, err = parseIntField(v.field, v.record, nil)
 		default:
 			assert.Fail(t, "unknown primitive field "+test.fieldType)
 		}
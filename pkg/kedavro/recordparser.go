@@ -1,20 +1,35 @@
 package kedavro
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
-func parseRecordField(field *Field, record map[string]interface{}) (interface{}, error) {
-	// record is a bit different, first it doesn't use default, and second we just
-	// want to check if the object exists to start again processing a new record
+func parseRecordField(field *Field, record map[string]interface{}, state *parseState) (interface{}, error) {
+	// record is a bit different, we just want to check if the object exists to start
+	// again processing a new record, if it doesn't we use the default of the record
 	value, ok := record[field.Name]
 	if !ok {
-		return nil, fmt.Errorf("value for field \"%s\" not found", field.Name)
+		if field.HasNativeDefault {
+			return copyNativeValue(field.NativeDefault), nil
+		}
+		if field.HasDefault {
+			return getNativeDefaultValue(field, field.DefaultValue, field.Name)
+		}
+		return nil, newReasonError(ReasonMissingValue, nil, fmt.Errorf("value for field \"%s\" not found", field.Name))
 	}
 
 	valueAsMap, ok := value.(map[string]interface{})
+	if s, isString := value.(string); !ok && isString && field.Opts.IsEmbeddedJSON {
+		// the record is encoded as a JSON string inside the JSON record
+		if err := json.Unmarshal([]byte(s), &valueAsMap); err != nil || valueAsMap == nil {
+			return nil, newReasonError(ReasonInvalidJSON, value, fmt.Errorf("value \"%v\" in field \"%s\" is not a JSON object", value, field.Name))
+		}
+		ok = true
+		state.addCoercion(CoercionEmbeddedJSON, value, valueAsMap)
+	}
 	if !ok {
-		return nil, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"record\"", value, field.Name)
+		return nil, newReasonError(ReasonTypeMismatch, value, fmt.Errorf("value \"%v\" in field \"%s\" in not of type \"record\"", value, field.Name))
 	}
-	return parseRecord(field, valueAsMap)
+	return parseRecord(field, valueAsMap, state)
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/linkedin/goavro/v2"
 	"github.com/ouzi-dev/avro-kedavro/pkg/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -130,7 +131,7 @@ func TestRecordType(t *testing.T) {
 	}
 
 	for _, v := range tests {
-		result, err := parseRecordField(v.field, v.record)
+		result, err := parseRecordField(v.field, v.record, nil)
 		if v.isError {
 			assert.Error(t, err)
 		} else {
@@ -198,8 +199,249 @@ func TestRecordWithRecordAsType(t *testing.T) {
 
 	native := getJSONAsNative(testJSONRecord, t)
 
-	result, err := parseRecordField(field, native)
+	result, err := parseRecordField(field, native, nil)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
 }
+
+const recordDefaultsSchema = `
+{
+	"name": "Wizard",
+	"type": "record",
+	"fields": [
+		{
+			"name": "name",
+			"type": "string"
+		},
+		{
+			"name": "address",
+			"type": {
+				"name": "address",
+				"type": "record",
+				"fields": [
+					{
+						"name": "city",
+						"type": "string"
+					},
+					{
+						"name": "street",
+						"type": "string",
+						"default": "unknown"
+					}
+				]
+			},
+			"default": {"city": "unknown"}
+		},
+		{
+			"name": "wand",
+			"type": "record",
+			"fields": [
+				{
+					"name": "wood",
+					"type": "string",
+					"default": "unknown"
+				},
+				{
+					"name": "core",
+					"type": "record",
+					"fields": [
+						{
+							"name": "material",
+							"type": ["null", "string"],
+							"default": null
+						}
+					]
+				}
+			]
+		}
+	]
+}
+`
+
+func TestRecordDefaults(t *testing.T) {
+	p, err := NewParser(recordDefaultsSchema)
+	assert.NoError(t, err)
+
+	result, err := p.Parse([]byte(`{"name": "Harry", "wand": {"core": {}}}`))
+	assert.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"name": "Harry",
+		"address": map[string]interface{}{
+			"city":   "unknown",
+			"street": "unknown",
+		},
+		"wand": map[string]interface{}{
+			"wood": "unknown",
+			"core": map[string]interface{}{
+				"material": nil,
+			},
+		},
+	}
+	assert.Equal(t, expected, result)
+
+	codec, err := goavro.NewCodec(recordDefaultsSchema)
+	assert.NoError(t, err)
+
+	_, err = codec.BinaryFromNative(nil, result)
+	assert.NoError(t, err)
+
+	// without the option records without default are still required
+	_, err = p.Parse([]byte(`{"name": "Harry"}`))
+	assert.Error(t, err)
+}
+
+func TestRecordDefaultFromFields(t *testing.T) {
+	p, err := NewParser(recordDefaultsSchema, WithRecordDefaultFromFields())
+	assert.NoError(t, err)
+
+	result, err := p.Parse([]byte(`{"name": "Harry"}`))
+	assert.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"name": "Harry",
+		"address": map[string]interface{}{
+			"city":   "unknown",
+			"street": "unknown",
+		},
+		"wand": map[string]interface{}{
+			"wood": "unknown",
+			"core": map[string]interface{}{
+				"material": nil,
+			},
+		},
+	}
+	assert.Equal(t, expected, result)
+
+	schema := `
+	{
+		"name": "Wizard",
+		"type": "record",
+		"fields": [
+			{
+				"name": "wand",
+				"type": "record",
+				"fields": [
+					{
+						"name": "wood",
+						"type": "string",
+						"default": "unknown"
+					},
+					{
+						"name": "core",
+						"type": "string"
+					}
+				]
+			}
+		]
+	}
+	`
+
+	p, err = NewParser(schema, WithRecordDefaultFromFields())
+	assert.NoError(t, err)
+
+	// core doesn't have default, so wand can't be built from the defaults of its fields
+	_, err = p.Parse([]byte(`{}`))
+	assert.Error(t, err)
+}
+
+func TestRecordEmbeddedJSON(t *testing.T) {
+	schema := `
+	{
+		"name": "Event",
+		"type": "record",
+		"fields": [
+			{
+				"name": "id",
+				"type": "long"
+			},
+			{
+				"name": "meta",
+				"type": "record",
+				"fields": [
+					{
+						"name": "version",
+						"type": "int"
+					},
+					{
+						"name": "source",
+						"type": "record",
+						"fields": [
+							{
+								"name": "name",
+								"type": "string"
+							}
+						]
+					}
+				]
+			}
+		]
+	}
+	`
+
+	type testItem struct {
+		record   string
+		isError  bool
+		expected interface{}
+	}
+
+	tests := []testItem{
+		{
+			record: `{"id": 1, "meta": "{\"version\": 2, \"source\": \"{\\\"name\\\": \\\"owl\\\"}\"}"}`,
+			expected: map[string]interface{}{
+				"id": int64(1),
+				"meta": map[string]interface{}{
+					"version": int32(2),
+					"source": map[string]interface{}{
+						"name": "owl",
+					},
+				},
+			},
+		},
+		{
+			record: `{"id": 1, "meta": {"version": 2, "source": "{\"name\": \"owl\"}"}}`,
+			expected: map[string]interface{}{
+				"id": int64(1),
+				"meta": map[string]interface{}{
+					"version": int32(2),
+					"source": map[string]interface{}{
+						"name": "owl",
+					},
+				},
+			},
+		},
+		{
+			record:  `{"id": 1, "meta": "{\"version\": 2"}`,
+			isError: true,
+		},
+		{
+			record:  `{"id": 1, "meta": "[1, 2]"}`,
+			isError: true,
+		},
+		{
+			record:  `{"id": 1, "meta": "null"}`,
+			isError: true,
+		},
+	}
+
+	p, err := NewParser(schema, WithEmbeddedJSON())
+	assert.NoError(t, err)
+
+	for _, v := range tests {
+		result, err := p.Parse([]byte(v.record))
+		if v.isError {
+			assert.Error(t, err, v.record)
+			continue
+		}
+		assert.NoError(t, err, v.record)
+		assert.Equal(t, v.expected, result, v.record)
+	}
+
+	// without the option embedded JSON is not a record
+	p, err = NewParser(schema)
+	assert.NoError(t, err)
+
+	_, err = p.Parse([]byte(tests[0].record))
+	assert.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package kedavro
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrSchemaNotFound is returned by a schema registry when a subject, a schema
+// or an id isn't registered
+var ErrSchemaNotFound = errors.New("schema not found")
+
+// SchemaRegistry looks up and registers schemas by subject, like the confluent
+// schema registry
+type SchemaRegistry interface {
+	// Register returns the id of the schema in the subject, registering it
+	// if it isn't registered yet
+	Register(subject string, schema string) (uint32, error)
+	// GetID returns the id of a schema already registered in the subject
+	GetID(subject string, schema string) (uint32, error)
+	// GetSchema returns the schema registered with the id
+	GetSchema(id uint32) (string, error)
+}
+
+type memorySchemaRegistry struct {
+	mutex    sync.RWMutex
+	nextID   uint32
+	ids      map[string]uint32
+	schemas  map[uint32]string
+	subjects map[string]map[uint32]bool
+}
+
+// NewMemorySchemaRegistry returns a SchemaRegistry keeping the schemas in
+// memory, the same schema gets the same id in every subject
+func NewMemorySchemaRegistry() SchemaRegistry {
+	return &memorySchemaRegistry{
+		nextID:   1,
+		ids:      map[string]uint32{},
+		schemas:  map[uint32]string{},
+		subjects: map[string]map[uint32]bool{},
+	}
+}
+
+func (r *memorySchemaRegistry) Register(subject string, schema string) (uint32, error) {
+	key, err := getSchemaKey(schema)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	id, ok := r.ids[key]
+	if !ok {
+		id = r.nextID
+		r.nextID++
+		r.ids[key] = id
+		r.schemas[id] = schema
+	}
+
+	if _, ok := r.subjects[subject]; !ok {
+		r.subjects[subject] = map[uint32]bool{}
+	}
+	r.subjects[subject][id] = true
+
+	return id, nil
+}
+
+func (r *memorySchemaRegistry) GetID(subject string, schema string) (uint32, error) {
+	key, err := getSchemaKey(schema)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	id, ok := r.ids[key]
+	if !ok || !r.subjects[subject][id] {
+		return 0, fmt.Errorf("subject \"%s\": %w", subject, ErrSchemaNotFound)
+	}
+
+	return id, nil
+}
+
+func (r *memorySchemaRegistry) GetSchema(id uint32) (string, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	schema, ok := r.schemas[id]
+	if !ok {
+		return "", fmt.Errorf("id %d: %w", id, ErrSchemaNotFound)
+	}
+
+	return schema, nil
+}
+
+// getSchemaKey compacts the schema, so the same schema with different
+// whitespace gets the same id
+func getSchemaKey(schema string) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, []byte(schema)); err != nil {
+		return "", fmt.Errorf("invalid schema: %v", err)
+	}
+
+	return buf.String(), nil
+}
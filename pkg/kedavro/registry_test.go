@@ -0,0 +1,53 @@
+package kedavro
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemorySchemaRegistry(t *testing.T) {
+	registry := NewMemorySchemaRegistry()
+
+	_, err := registry.GetID("wizards-value", writerSchema)
+	assert.True(t, errors.Is(err, ErrSchemaNotFound))
+	assert.EqualError(t, err, `subject "wizards-value": schema not found`)
+
+	id, err := registry.Register("wizards-value", writerSchema)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), id)
+
+	// registering the same schema again returns the same id
+	id, err = registry.Register("wizards-value", `{"name": "Wizard", "type": "record", "fields": [{"name": "name", "type": "string"}, {"name": "house", "type": ["null", "string"]}, {"name": "born", "type": "long", "logicalType": "timestamp-millis"}]}`)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), id)
+
+	id, err = registry.GetID("wizards-value", writerSchema)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), id)
+
+	// the schema isn't registered in other subjects
+	_, err = registry.GetID("muggles-value", writerSchema)
+	assert.True(t, errors.Is(err, ErrSchemaNotFound))
+
+	// but it keeps its id when it is
+	id, err = registry.Register("muggles-value", writerSchema)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), id)
+
+	id, err = registry.Register("wizards-value", binarySchema)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), id)
+
+	schema, err := registry.GetSchema(2)
+	assert.NoError(t, err)
+	assert.Equal(t, binarySchema, schema)
+
+	_, err = registry.GetSchema(3)
+	assert.True(t, errors.Is(err, ErrSchemaNotFound))
+	assert.EqualError(t, err, "id 3: schema not found")
+
+	_, err = registry.Register("wizards-value", "{")
+	assert.EqualError(t, err, "invalid schema: unexpected end of JSON input")
+}
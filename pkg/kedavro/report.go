@@ -0,0 +1,37 @@
+package kedavro
+
+// CoercionRule is the conversion applied to a value of the record
+type CoercionRule string
+
+const (
+	// CoercionStringToNumber a string parsed as a number, with WithStringToNumber()
+	CoercionStringToNumber CoercionRule = "string_to_number"
+	// CoercionStringToBool a string parsed as a boolean, with WithStringToBool()
+	CoercionStringToBool CoercionRule = "string_to_bool"
+	// CoercionSecondsToTimestamp a timestamp in seconds, with WithTimestampToMillis() or WithTimestampToMicros()
+	CoercionSecondsToTimestamp CoercionRule = "seconds_to_timestamp"
+	// CoercionDateTimeFormat a string parsed as a timestamp, with WithDateTimeFormat()
+	CoercionDateTimeFormat CoercionRule = "datetime_format"
+	// CoercionNowForNull a null timestamp set to the current time, with WithNowForNullTimestamp()
+	CoercionNowForNull CoercionRule = "now_for_null"
+	// CoercionEmbeddedJSON a record encoded as a JSON string, with WithEmbeddedJSON()
+	CoercionEmbeddedJSON CoercionRule = "embedded_json"
+	// CoercionErrorPolicyNull a value that couldn't be parsed replaced by null
+	CoercionErrorPolicyNull CoercionRule = "error_policy_null"
+	// CoercionErrorPolicyDefault a value that couldn't be parsed replaced by the default value
+	CoercionErrorPolicyDefault CoercionRule = "error_policy_default"
+)
+
+// Coercion is a value of the record that was converted to fit the schema,
+// Path is the JSON path of the field, e.g. "$.order.price"
+type Coercion struct {
+	Path     string
+	Original interface{}
+	Rule     CoercionRule
+	Result   interface{}
+}
+
+// Report has the coercions applied to a record, in the order they were applied
+type Report struct {
+	Coercions []Coercion
+}
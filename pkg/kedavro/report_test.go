@@ -0,0 +1,160 @@
+package kedavro
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWithReport(t *testing.T) {
+	schema := `
+	{
+		"name": "Order",
+		"type": "record",
+		"fields": [
+			{
+				"name": "id",
+				"type": "long"
+			},
+			{
+				"name": "paid",
+				"type": "boolean"
+			},
+			{
+				"name": "total",
+				"type": ["null", "double"]
+			},
+			{
+				"name": "created",
+				"type": "long",
+				"logicalType": "timestamp-millis"
+			},
+			{
+				"name": "updated",
+				"type": "long",
+				"logicalType": "timestamp-millis"
+			},
+			{
+				"name": "units",
+				"type": "int",
+				"default": 1,
+				"kedavro.onError": "default"
+			},
+			{
+				"name": "meta",
+				"type": "record",
+				"fields": [
+					{
+						"name": "version",
+						"type": "int"
+					}
+				]
+			}
+		]
+	}
+	`
+
+	p, err := NewParser(schema, WithStringToNumber(), WithStringToBool(), WithTimestampToMillis(), WithDateTimeFormat(time.RFC3339), WithEmbeddedJSON())
+	assert.NoError(t, err)
+
+	record := `{"id": "1234", "paid": "true", "total": "9.99", "created": 1571128870, "updated": "2019-10-14T12:45:18Z", "units": "many", "meta": "{\"version\": \"2\"}"}`
+
+	result, report, err := p.ParseWithReport([]byte(record))
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+
+	updated, _ := time.Parse(time.RFC3339, "2019-10-14T12:45:18Z")
+	expected := []Coercion{
+		{Path: "$.id", Original: "1234", Rule: CoercionStringToNumber, Result: int64(1234)},
+		{Path: "$.paid", Original: "true", Rule: CoercionStringToBool, Result: true},
+		{Path: "$.total", Original: "9.99", Rule: CoercionStringToNumber, Result: 9.99},
+		{Path: "$.created", Original: int64(1571128870), Rule: CoercionSecondsToTimestamp, Result: time.Unix(1571128870, 0)},
+		{Path: "$.updated", Original: "2019-10-14T12:45:18Z", Rule: CoercionDateTimeFormat, Result: updated},
+		{Path: "$.units", Original: "many", Rule: CoercionErrorPolicyDefault, Result: int32(1)},
+		{Path: "$.meta", Original: `{"version": "2"}`, Rule: CoercionEmbeddedJSON, Result: map[string]interface{}{"version": "2"}},
+		{Path: "$.meta.version", Original: "2", Rule: CoercionStringToNumber, Result: int32(2)},
+	}
+	assert.Equal(t, expected, report.Coercions)
+
+	// only the timestamps in seconds are coerced
+	record = `{"id": 1234, "paid": true, "total": null, "created": 1571128870, "updated": 1571128870, "units": 2, "meta": {"version": 2}}`
+
+	_, report, err = p.ParseWithReport([]byte(record))
+	assert.NoError(t, err)
+	if assert.Len(t, report.Coercions, 2) {
+		assert.Equal(t, "$.created", report.Coercions[0].Path)
+		assert.Equal(t, "$.updated", report.Coercions[1].Path)
+	}
+
+	_, report, err = p.ParseMapWithReport(map[string]interface{}{"id": "bleh"})
+	assert.Error(t, err)
+	assert.NotNil(t, report)
+}
+
+func TestParseWithReportNowForNull(t *testing.T) {
+	schema := `{"name": "Test", "type": "record", "fields": [{"name": "created", "type": "long", "logicalType": "timestamp-micros"}]}`
+
+	p, err := NewParser(schema, WithNowForNullTimestamp())
+	assert.NoError(t, err)
+
+	_, report, err := p.ParseWithReport([]byte(`{"created": null}`))
+	assert.NoError(t, err)
+	if assert.Len(t, report.Coercions, 1) {
+		assert.Equal(t, "$.created", report.Coercions[0].Path)
+		assert.Equal(t, CoercionNowForNull, report.Coercions[0].Rule)
+		assert.Nil(t, report.Coercions[0].Original)
+	}
+}
+
+func TestParseWithReportErrorPolicy(t *testing.T) {
+	schema := `
+	{
+		"name": "Order",
+		"type": "record",
+		"fields": [
+			{
+				"name": "id",
+				"type": "long"
+			},
+			{
+				"name": "shipping",
+				"type": "record",
+				"default": {"days": 5, "express": false},
+				"kedavro.onError": "default",
+				"fields": [
+					{
+						"name": "days",
+						"type": "int"
+					},
+					{
+						"name": "express",
+						"type": "boolean"
+					}
+				]
+			}
+		]
+	}
+	`
+
+	p, err := NewParser(schema, WithStringToNumber(), WithStringToBool())
+	assert.NoError(t, err)
+
+	// the coercion of shipping.days is dropped with the value of shipping
+	_, report, err := p.ParseWithReport([]byte(`{"id": "1", "shipping": {"days": "2", "express": "maybe"}}`))
+	assert.NoError(t, err)
+	expected := []Coercion{
+		{Path: "$.id", Original: "1", Rule: CoercionStringToNumber, Result: int64(1)},
+		{Path: "$.shipping", Original: "maybe", Rule: CoercionErrorPolicyDefault, Result: map[string]interface{}{"days": int32(5), "express": false}},
+	}
+	assert.Equal(t, expected, report.Coercions)
+
+	_, report, err = p.ParseWithReport([]byte(`{"id": "1", "shipping": {"days": "2", "express": "true"}}`))
+	assert.NoError(t, err)
+	expected = []Coercion{
+		{Path: "$.id", Original: "1", Rule: CoercionStringToNumber, Result: int64(1)},
+		{Path: "$.shipping.days", Original: "2", Rule: CoercionStringToNumber, Result: int32(2)},
+		{Path: "$.shipping.express", Original: "true", Rule: CoercionStringToBool, Result: true},
+	}
+	assert.Equal(t, expected, report.Coercions)
+}
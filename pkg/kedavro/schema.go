@@ -6,7 +6,7 @@ import (
 	"github.com/ouzi-dev/avro-kedavro/pkg/types"
 )
 
-type parseFieldFunction = func(f *Field, record map[string]interface{}) (interface{}, error)
+type parseFieldFunction = func(f *Field, record map[string]interface{}, state *parseState) (interface{}, error)
 
 type Field struct {
 	HasDefault   bool
@@ -18,24 +18,55 @@ type Field struct {
 	DefaultValue interface{}
 	Fields       []*Field
 	ParseField   parseFieldFunction
+	// Aliases are other names of the field in the JSON record, in order of preference
+	Aliases []string
+	// NativeDefault is DefaultValue already converted to the value goavro
+	// expects, it's only set when HasNativeDefault is true. Records without
+	// default can have it too if it was built from the defaults of their fields
+	HasNativeDefault bool
+	NativeDefault    interface{}
+	// Source is the JSON path in the record to read the value of the field from,
+	// instead of its name or aliases
+	Source     string
+	sourcePath []pathSegment
+	// normalizedKeys indexes the fields of the record by their normalized
+	// name and aliases, only when the normalized keys option is enabled
+	normalizedKeys map[string]normalizedKey
+	// CatchAll fields get every key of the JSON record not used by other fields
+	CatchAll      bool
+	catchAllField *Field
+	// OnError is the error policy of the field, only when HasOnError is true,
+	// otherwise the error policy of the parser is used
+	HasOnError bool
+	OnError    types.ErrorPolicy
 }
 
+// sourceAttribute is the attribute with the JSON path to read the field from
+const sourceAttribute = "kedavro.source"
+
+// fieldAttributes are the attributes that belong to the field and not to its
+// type when the type is defined as an object
+var fieldAttributes = []string{"default", "aliases", sourceAttribute, catchAllAttribute, onErrorAttribute}
+
 // nolint gomnd
 func validateUnionFields(name string, unionTypes []interface{}, defaultValue interface{}) error {
 	if len(unionTypes) != 2 {
 		return fmt.Errorf("only unions with two types are supported, union name \"%s\", types: %v", name, unionTypes)
 	}
 
-	if unionTypes[0] != "null" {
-		return fmt.Errorf("only unions where the first type is \"null\" are supported, union name \"%s\", types: %v", name, unionTypes)
+	for _, v := range unionTypes {
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("only strings are allowed as type in unions, union name \"%s\", types: %v", name, unionTypes)
+		}
 	}
 
-	if _, ok := unionTypes[1].(string); !ok {
-		return fmt.Errorf("only strings are allowed as type in unions, union name \"%s\", types: %v", name, unionTypes)
+	if (unionTypes[0] == types.NilType) == (unionTypes[1] == types.NilType) {
+		return fmt.Errorf("only unions where one of the types is \"null\" are supported, union name \"%s\", types: %v", name, unionTypes)
 	}
 
-	if defaultValue != nil {
-		return fmt.Errorf("only null is accepted as default value for unions, union name \"%s\", defaultValue: %v", name, defaultValue)
+	// the default value has to match the first type, if it's not null we check it when loading the defaults
+	if unionTypes[0] == types.NilType && defaultValue != nil {
+		return fmt.Errorf("only null is accepted as default value for unions where the first type is \"null\", union name \"%s\", defaultValue: %v", name, defaultValue)
 	}
 
 	return nil
@@ -55,19 +86,29 @@ func ParseSchemaField(f interface{}, opts types.Options) (*Field, error) {
 		return nil, fmt.Errorf("field type is required: %v", f)
 	}
 	defaultValue, hasDefault := fieldMap["default"]
-	var err error
+	isCatchAll, err := getCatchAll(fieldMap)
+	if err != nil {
+		return nil, err
+	}
 	var fieldType types.FieldType
 	var parserFunction parseFieldFunction
 	switch t := typeValue.(type) {
 	case string:
 		fieldType = types.Primitive
+		if t == types.MapType {
+			if err := validateCatchAllField(name, fieldMap, isCatchAll); err != nil {
+				return nil, err
+			}
+			parserFunction = parseCatchAllField
+			break
+		}
 		parserFunction, err = getParseFieldFunction(t)
 		if err != nil {
 			return nil, err
 		}
 	case []interface{}:
 		fieldType = types.Union
-		// for now we only accept Unions with max two items, and the first one has to be null
+		// for now we only accept Unions with max two items, and one of them has to be null
 		if err := validateUnionFields(name, t, defaultValue); err != nil {
 			return nil, err
 		}
@@ -78,6 +119,9 @@ func ParseSchemaField(f interface{}, opts types.Options) (*Field, error) {
 	default:
 		return nil, fmt.Errorf("unknown field type %v in: %v", t, f)
 	}
+	if isCatchAll && typeValue != types.MapType {
+		return nil, fmt.Errorf("catch-all field \"%s\" has to be of type map of strings", name)
+	}
 	var logicalType string
 	logicalTypeValue, ok := fieldMap["logicalType"]
 	if !ok {
@@ -88,6 +132,18 @@ func ParseSchemaField(f interface{}, opts types.Options) (*Field, error) {
 			return nil, fmt.Errorf("logicaltype has to be a string, but it's current value is: %v", logicalTypeValue)
 		}
 	}
+	aliases, err := getAliases(fieldMap)
+	if err != nil {
+		return nil, err
+	}
+	source, sourcePath, err := getSource(fieldMap)
+	if err != nil {
+		return nil, err
+	}
+	onError, hasOnError, err := getOnError(fieldMap)
+	if err != nil {
+		return nil, err
+	}
 	var fields []*Field
 	mapFieldsValue, ok := fieldMap["fields"]
 	if !ok {
@@ -106,13 +162,79 @@ func ParseSchemaField(f interface{}, opts types.Options) (*Field, error) {
 		DefaultValue: defaultValue,
 		Fields:       fields,
 		LogicalType:  logicalType,
+		Aliases:      aliases,
 		TypeValue:    typeValue,
 		Opts:         opts,
 		ParseField:   parserFunction,
 	}
+	if isCatchAll {
+		parsedField.CatchAll = true
+	}
+	if hasOnError {
+		if onError == types.ErrorPolicyNull && !isNullableField(parsedField) {
+			return nil, fmt.Errorf("error policy \"null\" is only supported for nullable fields, field name \"%s\"", name)
+		}
+		parsedField.HasOnError = true
+		parsedField.OnError = onError
+	}
+	if parsedField.catchAllField, err = getCatchAllField(name, fields); err != nil {
+		return nil, err
+	}
+	if len(source) > 0 {
+		parsedField.Source = source
+		parsedField.sourcePath = sourcePath
+	}
+	if opts.IsNormalizeKeys && isRecordField(parsedField) {
+		parsedField.normalizedKeys, err = getNormalizedKeys(name, fields)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return parsedField, nil
 }
 
+func getAliases(fieldMap map[string]interface{}) ([]string, error) {
+	aliasesValue, ok := fieldMap["aliases"]
+	if !ok {
+		return nil, nil
+	}
+
+	listAliases, ok := aliasesValue.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("aliases has to be an array of strings, but it's current value is: %v", aliasesValue)
+	}
+
+	aliases := make([]string, 0, len(listAliases))
+	for _, v := range listAliases {
+		alias, ok := v.(string)
+		if !ok || len(alias) == 0 {
+			return nil, fmt.Errorf("aliases has to be an array of strings, but it's current value is: %v", aliasesValue)
+		}
+		aliases = append(aliases, alias)
+	}
+
+	return aliases, nil
+}
+
+func getSource(fieldMap map[string]interface{}) (string, []pathSegment, error) {
+	sourceValue, ok := fieldMap[sourceAttribute]
+	if !ok {
+		return "", nil, nil
+	}
+
+	source, ok := sourceValue.(string)
+	if !ok {
+		return "", nil, fmt.Errorf("%s has to be a string, but it's current value is: %v", sourceAttribute, sourceValue)
+	}
+
+	path, err := parseSourcePath(source)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return source, path, nil
+}
+
 func getParseFieldFunction(fieldType string) (parseFieldFunction, error) {
 	switch fieldType {
 	case types.StringType:
@@ -138,9 +260,40 @@ func getParseFieldFunction(fieldType string) (parseFieldFunction, error) {
 	}
 }
 
+func getParseValueFunction(fieldType string) (valueParserFunction, error) {
+	switch fieldType {
+	case types.StringType:
+		return parseStringValue, nil
+	case types.NilType:
+		return parseNilValue, nil
+	case types.BoolType:
+		return parseBoolValue, nil
+	case types.BytesType:
+		return parseBytesValue, nil
+	case types.FloatType:
+		return parseFloatValue, nil
+	case types.DoubleType:
+		return parseDoubleValue, nil
+	case types.LongType:
+		return parseLongValue, nil
+	case types.IntType:
+		return parseIntValue, nil
+	default:
+		return nil, fmt.Errorf("type \"%s\" not supported", fieldType)
+	}
+}
+
 func getObjectType(parentField, childField map[string]interface{}, opts types.Options) (*Field, error) {
 	//now we just keep the name of the parent so...
 	childField["name"] = parentField["name"]
+	// and the attributes of the field, the child only describes the type
+	for _, attribute := range fieldAttributes {
+		if value, ok := parentField[attribute]; ok {
+			childField[attribute] = value
+		} else {
+			delete(childField, attribute)
+		}
+	}
 	return ParseSchemaField(childField, opts)
 }
 
@@ -278,6 +278,19 @@ func TestValidUnions(t *testing.T) {
 		},
 		{
 			union:   []interface{}{"long", "null"},
+			isError: false,
+		},
+		{
+			union:        []interface{}{"long", "null"},
+			isError:      false,
+			defaultValue: 123,
+		},
+		{
+			union:   []interface{}{"null", "null"},
+			isError: true,
+		},
+		{
+			union:   []interface{}{"long", "string"},
 			isError: true,
 		},
 		{
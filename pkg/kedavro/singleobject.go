@@ -0,0 +1,107 @@
+package kedavro
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// singleObjectMarker are the first two bytes of a message using the avro single
+// object encoding, followed by the CRC-64-AVRO fingerprint of the schema as a
+// 8 bytes little endian integer
+var singleObjectMarker = []byte{0xc3, 0x01}
+
+const singleObjectHeaderLength = 10
+
+func (p *parser) Fingerprint() (uint64, error) {
+	if p.fingerprintErr != nil {
+		return 0, fmt.Errorf("cannot get fingerprint of schema: %v", p.fingerprintErr)
+	}
+
+	return p.fingerprint, nil
+}
+
+func (p *parser) ParseToSingleObject(buf []byte, record []byte) ([]byte, error) {
+	jsonRecord, err := unmarshalRecord(record)
+	if err != nil {
+		return buf, err
+	}
+
+	return p.ParseMapToSingleObject(buf, jsonRecord)
+}
+
+func (p *parser) ParseMapToSingleObject(buf []byte, record map[string]interface{}) ([]byte, error) {
+	fingerprint, err := p.Fingerprint()
+	if err != nil {
+		return buf, err
+	}
+
+	start := len(buf)
+	buf = appendSingleObjectHeader(buf, fingerprint)
+
+	result, err := p.ParseMapToBinary(buf, record)
+	if err != nil {
+		return result[:start], err
+	}
+
+	return result, nil
+}
+
+func appendSingleObjectHeader(buf []byte, fingerprint uint64) []byte {
+	header := make([]byte, singleObjectHeaderLength)
+	copy(header, singleObjectMarker)
+	binary.LittleEndian.PutUint64(header[len(singleObjectMarker):], fingerprint)
+
+	return append(buf, header...)
+}
+
+// Resolver picks the parser of the schema used to encode a single object
+// encoded message
+type Resolver struct {
+	parsers map[uint64]Parser
+}
+
+// NewResolver returns a Resolver for the schemas of the parsers, two parsers
+// can't have the same schema
+func NewResolver(parsers ...Parser) (*Resolver, error) {
+	resolver := &Resolver{
+		parsers: map[uint64]Parser{},
+	}
+
+	for _, p := range parsers {
+		fingerprint, err := p.Fingerprint()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := resolver.parsers[fingerprint]; ok {
+			return nil, fmt.Errorf("duplicated parser for fingerprint %016x", fingerprint)
+		}
+		resolver.parsers[fingerprint] = p
+	}
+
+	return resolver, nil
+}
+
+// Get returns the parser of the schema with the fingerprint
+func (r *Resolver) Get(fingerprint uint64) (Parser, error) {
+	p, ok := r.parsers[fingerprint]
+	if !ok {
+		return nil, fmt.Errorf("fingerprint %016x: %w", fingerprint, ErrSchemaNotFound)
+	}
+
+	return p, nil
+}
+
+// Resolve returns the parser of the schema used to encode a single object
+// encoded message, and the avro binary encoded record of the message
+func (r *Resolver) Resolve(message []byte) (Parser, []byte, error) {
+	if len(message) < singleObjectHeaderLength || message[0] != singleObjectMarker[0] || message[1] != singleObjectMarker[1] {
+		return nil, nil, fmt.Errorf("message is not single object encoded")
+	}
+
+	p, err := r.Get(binary.LittleEndian.Uint64(message[len(singleObjectMarker):singleObjectHeaderLength]))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return p, message[singleObjectHeaderLength:], nil
+}
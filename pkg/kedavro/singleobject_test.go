@@ -0,0 +1,116 @@
+package kedavro
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseToSingleObject(t *testing.T) {
+	codec, err := goavro.NewCodec(writerSchema)
+	assert.NoError(t, err)
+
+	p, err := NewParser(writerSchema)
+	assert.NoError(t, err)
+	fingerprint, err := p.Fingerprint()
+	assert.NoError(t, err)
+	assert.Equal(t, codec.Rabin, fingerprint)
+
+	for _, v := range writerRecords {
+		native, err := p.Parse([]byte(v))
+		assert.NoError(t, err, v)
+
+		expected, err := codec.SingleFromNative(nil, native)
+		assert.NoError(t, err, v)
+
+		result, err := p.ParseToSingleObject([]byte("prefix"), []byte(v))
+		assert.NoError(t, err, v)
+		assert.Equal(t, append([]byte("prefix"), expected...), result, v)
+	}
+
+	result, err := p.ParseToSingleObject([]byte("prefix"), []byte(`{"name": "hedwig", "house": null, "born": "never"}`))
+	assert.Error(t, err)
+	assert.Equal(t, []byte("prefix"), result)
+}
+
+func TestResolver(t *testing.T) {
+	wizards, err := NewParser(writerSchema)
+	assert.NoError(t, err)
+	muggles, err := NewParser(binarySchema)
+	assert.NoError(t, err)
+
+	resolver, err := NewResolver(wizards, muggles)
+	assert.NoError(t, err)
+
+	fingerprint, err := muggles.Fingerprint()
+	assert.NoError(t, err)
+	p, err := resolver.Get(fingerprint)
+	assert.NoError(t, err)
+	assert.Equal(t, muggles, p)
+
+	message, err := wizards.ParseToSingleObject(nil, []byte(writerRecords[0]))
+	assert.NoError(t, err)
+	expected, err := wizards.ParseToBinary(nil, []byte(writerRecords[0]))
+	assert.NoError(t, err)
+
+	p, payload, err := resolver.Resolve(message)
+	assert.NoError(t, err)
+	assert.Equal(t, wizards, p)
+	assert.Equal(t, expected, payload)
+
+	_, _, err = resolver.Resolve(expected)
+	assert.EqualError(t, err, "message is not single object encoded")
+
+	_, _, err = resolver.Resolve([]byte{0xc3, 0x01, 0, 0, 0, 0, 0, 0, 0, 1})
+	assert.True(t, errors.Is(err, ErrSchemaNotFound))
+	assert.EqualError(t, err, "fingerprint 0100000000000000: schema not found")
+
+	// the same schema with different options can't be resolved
+	other, err := NewParser(writerSchema, WithStringToNumber())
+	assert.NoError(t, err)
+	_, err = NewResolver(wizards, other)
+	fingerprint, _ = wizards.Fingerprint()
+	assert.EqualError(t, err, fmt.Sprintf("duplicated parser for fingerprint %016x", fingerprint))
+}
+
+func TestSchemaWithoutFingerprint(t *testing.T) {
+	// nested records without name get the name of the field, but they don't have
+	// a canonical form
+	schema := `
+	{
+		"name": "Wizard",
+		"type": "record",
+		"fields": [
+			{"name": "name", "type": "string"},
+			{"name": "address", "type": {"type": "record", "fields": [{"name": "street", "type": "string"}]}}
+		]
+	}
+	`
+	record := `{"name": "harry", "address": {"street": "privet drive"}}`
+
+	p, err := NewParser(schema)
+	assert.NoError(t, err)
+
+	result, err := p.Parse([]byte(record))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "harry", "address": map[string]interface{}{"street": "privet drive"}}, result)
+
+	expectedErr := `cannot get fingerprint of schema: missing name in schema of type "record"`
+
+	_, err = p.Fingerprint()
+	assert.EqualError(t, err, expectedErr)
+
+	result, err = p.ParseToSingleObject([]byte("prefix"), []byte(record))
+	assert.EqualError(t, err, expectedErr)
+	assert.Equal(t, []byte("prefix"), result)
+
+	result, err = p.ParseMapToSingleObject([]byte("prefix"), map[string]interface{}{"name": "harry"})
+	assert.EqualError(t, err, expectedErr)
+	assert.Equal(t, []byte("prefix"), result)
+
+	_, err = NewResolver(p)
+	assert.EqualError(t, err, expectedErr)
+}
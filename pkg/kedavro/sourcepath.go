@@ -0,0 +1,90 @@
+package kedavro
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is a step in a source path, a key of an object or an index of an array
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseSourcePath parses a JSON path like "$.payload.user.id", "$['user id']" or
+// "$.items[0]", where "$" is the JSON object of the record that has the field
+// nolint gocyclo
+func parseSourcePath(path string) ([]pathSegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("source path \"%s\" has to start with \"$\"", path)
+	}
+
+	segments := []pathSegment{}
+	rest := path[1:]
+	for len(rest) > 0 {
+		switch {
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if len(key) == 0 {
+				return nil, fmt.Errorf("empty key in source path \"%s\"", path)
+			}
+			segments = append(segments, pathSegment{key: key})
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "['") || strings.HasPrefix(rest, "[\""):
+			quote := rest[1]
+			end := strings.IndexByte(rest[2:], quote)
+			if end < 0 || !strings.HasPrefix(rest[end+3:], "]") {
+				return nil, fmt.Errorf("unterminated key in source path \"%s\"", path)
+			}
+			segments = append(segments, pathSegment{key: rest[2 : end+2]})
+			rest = rest[end+4:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated index in source path \"%s\"", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("index \"%s\" in source path \"%s\" is not valid", rest[1:end], path)
+			}
+			segments = append(segments, pathSegment{index: index, isIndex: true})
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected \"%s\" in source path \"%s\"", rest, path)
+		}
+	}
+
+	return segments, nil
+}
+
+// getPathValue returns the value in the path, and false if any step of the path doesn't exist
+func getPathValue(record map[string]interface{}, path []pathSegment) (interface{}, bool) {
+	var current interface{} = record
+	for _, v := range path {
+		if v.isIndex {
+			list, ok := current.([]interface{})
+			if !ok || v.index >= len(list) {
+				return nil, false
+			}
+			current = list[v.index]
+			continue
+		}
+
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = object[v.key]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
@@ -0,0 +1,162 @@
+package kedavro
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSourcePath(t *testing.T) {
+	type testItem struct {
+		path     string
+		isError  bool
+		expected []pathSegment
+	}
+
+	tests := []testItem{
+		{
+			path:     "$",
+			expected: []pathSegment{},
+		},
+		{
+			path:     "$.payload.user.id",
+			expected: []pathSegment{{key: "payload"}, {key: "user"}, {key: "id"}},
+		},
+		{
+			path:     "$['user id'][\"first.name\"]",
+			expected: []pathSegment{{key: "user id"}, {key: "first.name"}},
+		},
+		{
+			path:     "$.items[2].price",
+			expected: []pathSegment{{key: "items"}, {index: 2, isIndex: true}, {key: "price"}},
+		},
+		{
+			path:    "payload.user",
+			isError: true,
+		},
+		{
+			path:    "$..user",
+			isError: true,
+		},
+		{
+			path:    "$.items[a]",
+			isError: true,
+		},
+		{
+			path:    "$.items[-1]",
+			isError: true,
+		},
+		{
+			path:    "$['user",
+			isError: true,
+		},
+		{
+			path:    "$user",
+			isError: true,
+		},
+	}
+
+	for _, v := range tests {
+		result, err := parseSourcePath(v.path)
+		if v.isError {
+			assert.Error(t, err, v.path)
+		} else {
+			assert.NoError(t, err, v.path)
+		}
+		assert.Equal(t, v.expected, result, v.path)
+	}
+}
+
+func TestSourcePath(t *testing.T) {
+	schema := `
+	{
+		"name": "Event",
+		"type": "record",
+		"fields": [
+			{
+				"name": "user_id",
+				"type": "long",
+				"kedavro.source": "$.payload.user.id"
+			},
+			{
+				"name": "first_item",
+				"type": ["null", "string"],
+				"default": null,
+				"kedavro.source": "$.payload.items[0]"
+			},
+			{
+				"name": "type",
+				"type": "string",
+				"default": "unknown",
+				"kedavro.source": "$.meta['event type']"
+			},
+			{
+				"name": "device",
+				"type": {
+					"name": "device",
+					"type": "record",
+					"fields": [
+						{
+							"name": "os",
+							"type": "string",
+							"kedavro.source": "$.system.name"
+						}
+					]
+				},
+				"kedavro.source": "$.payload.device"
+			}
+		]
+	}
+	`
+
+	p, err := NewParser(schema)
+	assert.NoError(t, err)
+
+	result, err := p.Parse([]byte(`
+	{
+		"user_id": 1,
+		"meta": {"event type": "login"},
+		"payload": {
+			"user": {"id": 1234},
+			"items": ["wand", "broom"],
+			"device": {"system": {"name": "linux"}}
+		}
+	}`))
+	assert.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"user_id":    int64(1234),
+		"first_item": map[string]interface{}{"string": "wand"},
+		"type":       "login",
+		"device":     map[string]interface{}{"os": "linux"},
+	}
+	assert.Equal(t, expected, result)
+
+	result, err = p.Parse([]byte(`{"payload": {"user": {"id": 1234}, "items": [], "device": {"system": {"name": "linux"}}}}`))
+	assert.NoError(t, err)
+
+	expected = map[string]interface{}{
+		"user_id":    int64(1234),
+		"first_item": nil,
+		"type":       "unknown",
+		"device":     map[string]interface{}{"os": "linux"},
+	}
+	assert.Equal(t, expected, result)
+
+	// the name of the field is not used when it has a source
+	_, err = p.Parse([]byte(`{"user_id": 1234, "payload": {"device": {"system": {"name": "linux"}}}}`))
+	assert.Error(t, err)
+
+	p, err = NewParser(schema, WithNormalizedKeys())
+	assert.NoError(t, err)
+
+	result, err = p.Parse([]byte(`{"payload": {"user": {"id": 1234}, "items": [], "device": {"system": {"name": "linux"}}}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+
+	_, err = NewParser(`{"name": "Test", "type": "record", "fields": [{"name": "test", "type": "string", "kedavro.source": 1234}]}`)
+	assert.Error(t, err)
+
+	_, err = NewParser(`{"name": "Test", "type": "record", "fields": [{"name": "test", "type": "string", "kedavro.source": "test"}]}`)
+	assert.Error(t, err)
+}
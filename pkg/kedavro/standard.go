@@ -0,0 +1,189 @@
+package kedavro
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ouzi-dev/avro-kedavro/pkg/types"
+)
+
+// kedavroAttributePrefix is the prefix of the attributes only kedavro knows
+// about, like kedavro.source
+const kedavroAttributePrefix = "kedavro."
+
+// standardFieldAttributes are the attributes of a field in the avro
+// specification, the rest of the attributes of a field declaring its type
+// belong to the type. The aliases of kedavro are keys of the JSON record, not
+// names of the field in other schemas, so they are not kept
+var standardFieldAttributes = map[string]bool{
+	"name":    true,
+	"doc":     true,
+	"default": true,
+	"order":   true,
+}
+
+// getStandardSchema returns the schema as defined in the avro specification,
+// for readers that don't know about kedavro: without kedavro attributes, with
+// the types declared in the field itself moved to the type of the field and
+// with the records without name named after their field
+func getStandardSchema(schemaString string) (string, error) {
+	var schema interface{}
+	if err := json.Unmarshal([]byte(schemaString), &schema); err != nil {
+		return "", fmt.Errorf("unmarshall schema failed: %v", err)
+	}
+
+	standard, err := getStandardType(schema, "")
+	if err != nil {
+		return "", err
+	}
+
+	result, err := json.Marshal(standard)
+	if err != nil {
+		return "", fmt.Errorf("marshall schema failed: %v", err)
+	}
+
+	return string(result), nil
+}
+
+// getStandardType returns the standard schema of a type, name is the name of
+// the field with the type, used for named types without name
+func getStandardType(schema interface{}, name string) (interface{}, error) {
+	switch v := schema.(type) {
+	case string:
+		return v, nil
+	case []interface{}:
+		result := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			standard, err := getStandardType(item, name)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, standard)
+		}
+		return result, nil
+	case map[string]interface{}:
+		return getStandardObject(v, name)
+	default:
+		return nil, fmt.Errorf("invalid schema \"%v\"", schema)
+	}
+}
+
+// nolint gocyclo
+func getStandardObject(schema map[string]interface{}, name string) (interface{}, error) {
+	typeName, ok := schema["type"].(string)
+	if !ok {
+		// the type is a schema itself, like the type of a field
+		typeValue, found := schema["type"]
+		if !found {
+			return nil, fmt.Errorf("missing type in schema \"%v\"", schema)
+		}
+		return getStandardType(typeValue, name)
+	}
+
+	result := map[string]interface{}{}
+	for k, v := range schema {
+		if !strings.HasPrefix(k, kedavroAttributePrefix) {
+			result[k] = v
+		}
+	}
+
+	switch typeName {
+	case types.RecordType, "error", "enum", "fixed":
+		if _, ok := result["name"]; !ok {
+			if len(name) == 0 {
+				return nil, fmt.Errorf("missing name in schema of type \"%s\"", typeName)
+			}
+			result["name"] = name
+		}
+	}
+
+	var err error
+	switch typeName {
+	case types.RecordType, "error":
+		fields, ok := schema["fields"].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("missing fields in schema of record \"%v\"", result["name"])
+		}
+		standardFields := make([]interface{}, 0, len(fields))
+		for _, f := range fields {
+			standardField, err := getStandardField(f)
+			if err != nil {
+				return nil, err
+			}
+			standardFields = append(standardFields, standardField)
+		}
+		result["fields"] = standardFields
+	case "array":
+		if result["items"], err = getStandardType(schema["items"], name); err != nil {
+			return nil, err
+		}
+	case types.MapType:
+		if result["values"], err = getStandardType(schema["values"], name); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func getStandardField(field interface{}) (interface{}, error) {
+	f, ok := field.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid field \"%v\"", field)
+	}
+
+	name, ok := f["name"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing name in field \"%v\"", field)
+	}
+
+	result := map[string]interface{}{}
+	for k, v := range f {
+		if standardFieldAttributes[k] {
+			result[k] = v
+		}
+	}
+
+	typeValue := f["type"]
+	if typeName, ok := typeValue.(string); ok && complexTypes[typeName] {
+		// complex types declared in the field itself, like {"name": "address", "type": "record", "fields": [...]},
+		// named types get the name of the field
+		declared := map[string]interface{}{}
+		for k, v := range f {
+			if !standardFieldAttributes[k] && k != "aliases" {
+				declared[k] = v
+			}
+		}
+		typeValue = declared
+	} else if logicalType, ok := f["logicalType"]; ok {
+		// the logical type of the field belongs to its type, or to the branch of the union that isn't null
+		typeValue = withLogicalType(typeValue, logicalType)
+	}
+
+	standardType, err := getStandardType(typeValue, name)
+	if err != nil {
+		return nil, err
+	}
+	result["type"] = standardType
+
+	return result, nil
+}
+
+func withLogicalType(typeValue interface{}, logicalType interface{}) interface{} {
+	switch v := typeValue.(type) {
+	case string:
+		if v == types.NilType {
+			return v
+		}
+		return map[string]interface{}{"type": v, "logicalType": logicalType}
+	case []interface{}:
+		result := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			result = append(result, withLogicalType(item, logicalType))
+		}
+		return result
+	default:
+		return typeValue
+	}
+}
@@ -0,0 +1,70 @@
+package kedavro
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStandardSchema(t *testing.T) {
+	type testItem struct {
+		schema   string
+		expected string
+		err      string
+	}
+
+	tests := []testItem{
+		{
+			schema:   `{"name": "Wizard", "type": "record", "fields": [{"name": "name", "type": "string", "aliases": ["Name"], "doc": "the name"}]}`,
+			expected: `{"fields":[{"doc":"the name","name":"name","type":"string"}],"name":"Wizard","type":"record"}`,
+		},
+		{
+			// the logical type of the field goes to the type, or to the branch of the union
+			schema:   `{"name": "Wizard", "type": "record", "fields": [{"name": "born", "type": "long", "logicalType": "timestamp-millis"}, {"name": "died", "type": ["null", "long"], "logicalType": "timestamp-micros", "default": null}]}`,
+			expected: `{"fields":[{"name":"born","type":{"logicalType":"timestamp-millis","type":"long"}},{"default":null,"name":"died","type":["null",{"logicalType":"timestamp-micros","type":"long"}]}],"name":"Wizard","type":"record"}`,
+		},
+		{
+			// records declared in the field itself are named after the field
+			schema:   `{"name": "Wizard", "namespace": "hogwarts", "type": "record", "fields": [{"name": "wand", "type": "record", "default": {"wood": "holly"}, "kedavro.onError": "default", "fields": [{"name": "wood", "type": "string", "kedavro.source": "$.material"}]}]}`,
+			expected: `{"fields":[{"default":{"wood":"holly"},"name":"wand","type":{"fields":[{"name":"wood","type":"string"}],"name":"wand","type":"record"}}],"name":"Wizard","namespace":"hogwarts","type":"record"}`,
+		},
+		{
+			schema:   `{"name": "Wizard", "type": "record", "fields": [{"name": "wand", "type": {"type": "record", "fields": [{"name": "wood", "type": "string"}]}}]}`,
+			expected: `{"fields":[{"name":"wand","type":{"fields":[{"name":"wood","type":"string"}],"name":"wand","type":"record"}}],"name":"Wizard","type":"record"}`,
+		},
+		{
+			schema:   `{"name": "Wizard", "type": "record", "fields": [{"name": "wand", "type": {"type": "record", "name": "Wand", "fields": [{"name": "wood", "type": "string"}]}}]}`,
+			expected: `{"fields":[{"name":"wand","type":{"fields":[{"name":"wood","type":"string"}],"name":"Wand","type":"record"}}],"name":"Wizard","type":"record"}`,
+		},
+		{
+			schema:   `{"name": "Wizard", "type": "record", "fields": [{"name": "extra", "type": {"type": "map", "values": "string"}, "kedavro.catchAll": true}]}`,
+			expected: `{"fields":[{"name":"extra","type":{"type":"map","values":"string"}}],"name":"Wizard","type":"record"}`,
+		},
+		{
+			schema: `{"type": "record", "fields": []}`,
+			err:    `missing name in schema of type "record"`,
+		},
+		{
+			schema: `{"name": "Wizard", "type": "record"}`,
+			err:    `missing fields in schema of record "Wizard"`,
+		},
+		{
+			schema: `{"name": "Wizard", "type": "record", "fields": [{"type": "string"}]}`,
+			err:    `missing name in field "map[type:string]"`,
+		},
+		{
+			schema: `{`,
+			err:    "unmarshall schema failed: unexpected end of JSON input",
+		},
+	}
+
+	for _, v := range tests {
+		result, err := getStandardSchema(v.schema)
+		if len(v.err) > 0 {
+			assert.EqualError(t, err, v.err, v.schema)
+			continue
+		}
+		assert.NoError(t, err, v.schema)
+		assert.Equal(t, v.expected, result, v.schema)
+	}
+}
@@ -0,0 +1,109 @@
+package kedavro
+
+// parseState is the position of the parser in the record being parsed, a nil
+// state or a state without parent is the root of the record
+type parseState struct {
+	parent *parseState
+	name   string
+	// report gets the coercions applied to the record, only when it's not nil
+	report *Report
+	// node is the explanation of the current field, only when explaining the record
+	node *ExplainNode
+	// buf gets the binary encoding of the record, only when it's not nil
+	buf *[]byte
+}
+
+func (s *parseState) child(name string) *parseState {
+	if s == nil {
+		s = &parseState{}
+	}
+	// the node of the child is only created for the fields of the record, see explainField
+	return &parseState{parent: s, name: name, report: s.report, buf: s.buf}
+}
+
+// path returns the JSON path of the current position, e.g. "$.address.city"
+func (s *parseState) path() string {
+	if s == nil || s.parent == nil {
+		return "$"
+	}
+	return s.parent.path() + "." + s.name
+}
+
+func (s *parseState) addCoercion(rule CoercionRule, original, result interface{}) {
+	if s != nil && s.node != nil {
+		s.node.Coercions = append(s.node.Coercions, rule)
+	}
+	if s == nil || s.report == nil {
+		return
+	}
+	s.report.Coercions = append(s.report.Coercions, Coercion{
+		Path:     s.path(),
+		Original: original,
+		Rule:     rule,
+		Result:   result,
+	})
+}
+
+// scratch gives the state its own report, so the coercions of the field and
+// its subfields can be dropped if the value of the field is not used, it returns
+// the report of the record to merge them with mergeScratch
+func (s *parseState) scratch() *Report {
+	if s == nil || s.report == nil {
+		return nil
+	}
+	report := s.report
+	s.report = &Report{}
+	return report
+}
+
+// mergeScratch adds the coercions of the field to the report of the record if
+// the value of the field is used, and reports to the record from now on
+func (s *parseState) mergeScratch(report *Report, used bool) {
+	if report == nil {
+		return
+	}
+	if used {
+		report.Coercions = append(report.Coercions, s.report.Coercions...)
+	}
+	s.report = report
+}
+
+// encodedRecord is the value of a record parsed with the binary encoding, its
+// fields are encoded while they are parsed instead of kept in a native map
+type encodedRecord struct{}
+
+// isEncoding returns if the binary encoding of the record is being written
+func (s *parseState) isEncoding() bool {
+	return s != nil && s.buf != nil
+}
+
+// encodedLen returns the length of the binary encoding of the record so far
+func (s *parseState) encodedLen() int {
+	if !s.isEncoding() {
+		return 0
+	}
+	return len(*s.buf)
+}
+
+// encodeField appends the binary encoding of the value of a field, start is the
+// length of the encoding before parsing the field. Records encode their fields
+// while they are parsed, so the value is only encoded if it isn't one of them,
+// or the value was replaced by the error policy of the field
+func (s *parseState) encodeField(field *Field, value interface{}, start int, replaced bool) error {
+	if !s.isEncoding() {
+		return nil
+	}
+	if replaced {
+		*s.buf = (*s.buf)[:start]
+	} else if _, ok := value.(encodedRecord); ok {
+		return nil
+	}
+
+	buf, err := appendBinary(*s.buf, field, value)
+	if err != nil {
+		return newReasonError(ReasonInvalidValue, value, err)
+	}
+	*s.buf = buf
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package kedavro
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// RecordError is the error parsing a record of a stream, with the line where
+// the record starts
+type RecordError struct {
+	Line int
+	Err  error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("record at line %d: %v", e.Line, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// RecordReader reads and parses the JSON records of a stream, one per line
+// (NDJSON) or as the elements of a JSON array, without loading the whole
+// stream in memory and without limits in the size of the records
+type RecordReader struct {
+	reader     *bufio.Reader
+	parser     Parser
+	isStarted  bool
+	isArray    bool
+	isDone     bool
+	count      int
+	line       int
+	record     interface{}
+	recordLine int
+	recordErr  error
+	err        error
+}
+
+// NewRecordReader returns a RecordReader parsing the records read from r, the
+// format is detected from the first character of the stream
+func NewRecordReader(r io.Reader, parser Parser) *RecordReader {
+	return &RecordReader{
+		reader: bufio.NewReader(r),
+		parser: parser,
+	}
+}
+
+// Next reads and parses the next record, it returns false at the end of the
+// stream or if the stream can't be read, see Err
+func (r *RecordReader) Next() bool {
+	if r.isDone || r.err != nil {
+		return false
+	}
+
+	if !r.isStarted {
+		r.isStarted = true
+		if err := r.detectFormat(); err != nil {
+			return r.stop(err)
+		}
+	}
+
+	var raw []byte
+	var err error
+	if r.isArray {
+		raw, err = r.readArrayElement()
+	} else {
+		raw, err = r.readLine()
+	}
+	if err != nil {
+		return r.stop(err)
+	}
+
+	r.count++
+	r.record, r.recordErr = r.parser.Parse(raw)
+	if r.recordErr != nil {
+		r.recordErr = &RecordError{Line: r.recordLine, Err: r.recordErr}
+	}
+
+	return true
+}
+
+// Record returns the last record read by Next, or a *RecordError if it can't
+// be parsed
+func (r *RecordReader) Record() (interface{}, error) {
+	return r.record, r.recordErr
+}
+
+// Line returns the line where the last record read by Next starts
+func (r *RecordReader) Line() int {
+	return r.recordLine
+}
+
+// Err returns the error that stopped reading the stream, nil at the end of the
+// stream
+func (r *RecordReader) Err() error {
+	return r.err
+}
+
+func (r *RecordReader) stop(err error) bool {
+	r.record, r.recordErr = nil, nil
+	if err == io.EOF {
+		r.isDone = true
+	} else {
+		r.err = err
+	}
+	return false
+}
+
+func (r *RecordReader) detectFormat() error {
+	b, err := r.peekNonSpace()
+	if err != nil {
+		return err
+	}
+
+	if b == '[' {
+		r.isArray = true
+		_, _ = r.reader.ReadByte()
+	}
+
+	return nil
+}
+
+func (r *RecordReader) readLine() ([]byte, error) {
+	for {
+		data, err := r.reader.ReadBytes('\n')
+		r.recordLine = r.line + 1
+		if len(data) > 0 && data[len(data)-1] == '\n' {
+			r.line++
+		}
+		// blank lines are skipped
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 {
+			return trimmed, nil
+		}
+		if err != nil {
+			return nil, r.readError(err)
+		}
+	}
+}
+
+// nolint gocyclo
+func (r *RecordReader) readArrayElement() ([]byte, error) {
+	b, err := r.readNonSpace()
+	if err != nil {
+		return nil, err
+	}
+
+	if b == ']' {
+		// only whitespace is allowed after the end of the array
+		if _, err := r.peekNonSpace(); err != io.EOF {
+			if err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("invalid JSON array at line %d: unexpected data after the end of the array", r.line+1)
+		}
+		return nil, io.EOF
+	}
+
+	if r.count > 0 {
+		if b != ',' {
+			return nil, fmt.Errorf("invalid JSON array at line %d: expected ',' or ']', found '%c'", r.line+1, b)
+		}
+		if b, err = r.readNonSpace(); err != nil {
+			return nil, err
+		}
+	}
+
+	if b == ',' || b == ']' {
+		return nil, fmt.Errorf("invalid JSON array at line %d: expected a record, found '%c'", r.line+1, b)
+	}
+
+	r.recordLine = r.line + 1
+	element := []byte{b}
+	depth := 0
+	isString := b == '"'
+	isEscaped := false
+
+	switch b {
+	case '{', '[':
+		depth = 1
+	case '"':
+	default:
+		// numbers, booleans and null end with the first delimiter
+		for {
+			b, err = r.reader.ReadByte()
+			if err == io.EOF {
+				return nil, fmt.Errorf("invalid JSON array at line %d: unexpected end of the array", r.line+1)
+			}
+			if err != nil {
+				return nil, r.readError(err)
+			}
+			if b == ',' || b == ']' || isSpace(b) {
+				_ = r.reader.UnreadByte()
+				return element, nil
+			}
+			element = append(element, b)
+		}
+	}
+
+	for depth > 0 || isString {
+		b, err = r.reader.ReadByte()
+		if err == io.EOF {
+			return nil, fmt.Errorf("invalid JSON array at line %d: unexpected end of the record starting at line %d", r.line+1, r.recordLine)
+		}
+		if err != nil {
+			return nil, r.readError(err)
+		}
+		element = append(element, b)
+
+		switch {
+		case b == '\n':
+			r.line++
+		case isEscaped:
+			isEscaped = false
+		case isString && b == '\\':
+			isEscaped = true
+		case b == '"':
+			isString = !isString
+		case isString:
+		case b == '{' || b == '[':
+			depth++
+		case b == '}' || b == ']':
+			depth--
+		}
+	}
+
+	return element, nil
+}
+
+// readNonSpace reads the next character that is not whitespace, failing if the
+// stream ends, as the array isn't closed
+func (r *RecordReader) readNonSpace() (byte, error) {
+	if _, err := r.peekNonSpace(); err != nil {
+		if err == io.EOF {
+			return 0, fmt.Errorf("invalid JSON array at line %d: unexpected end of the array", r.line+1)
+		}
+		return 0, err
+	}
+
+	b, _ := r.reader.ReadByte()
+	return b, nil
+}
+
+// peekNonSpace skips whitespace and returns the next character without
+// reading it
+func (r *RecordReader) peekNonSpace() (byte, error) {
+	for {
+		b, err := r.reader.ReadByte()
+		if err == io.EOF {
+			return 0, io.EOF
+		}
+		if err != nil {
+			return 0, r.readError(err)
+		}
+		if !isSpace(b) {
+			_ = r.reader.UnreadByte()
+			return b, nil
+		}
+		if b == '\n' {
+			r.line++
+		}
+	}
+}
+
+func (r *RecordReader) readError(err error) error {
+	if err == io.EOF {
+		return err
+	}
+	return fmt.Errorf("read stream failed at line %d: %v", r.line+1, err)
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
@@ -0,0 +1,125 @@
+package kedavro
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const streamSchema = `
+{
+	"name": "Wizard",
+	"type": "record",
+	"fields": [
+		{"name": "name", "type": "string"},
+		{"name": "id", "type": "long"}
+	]
+}
+`
+
+type streamRecord struct {
+	line   int
+	record interface{}
+	err    string
+}
+
+func readStream(t *testing.T, input string) ([]streamRecord, error) {
+	p, err := NewParser(streamSchema)
+	assert.NoError(t, err)
+
+	result := []streamRecord{}
+	reader := NewRecordReader(strings.NewReader(input), p)
+	for reader.Next() {
+		record, err := reader.Record()
+		r := streamRecord{line: reader.Line(), record: record}
+		if err != nil {
+			var recordErr *RecordError
+			assert.True(t, errors.As(err, &recordErr))
+			assert.Equal(t, reader.Line(), recordErr.Line)
+			r.err = err.Error()
+		}
+		result = append(result, r)
+	}
+
+	return result, reader.Err()
+}
+
+func TestRecordReader(t *testing.T) {
+	long := strings.Repeat("a", 100000)
+
+	tests := []struct {
+		input    string
+		expected []streamRecord
+	}{
+		{input: "", expected: []streamRecord{}},
+		{input: " \n\n", expected: []streamRecord{}},
+		{input: "[]", expected: []streamRecord{}},
+		{input: "\n [\n]\n", expected: []streamRecord{}},
+		{
+			input: "\n{\"name\": \"harry\", \"id\": 1}\n\n{\"name\": \"ron\", \"id\": 2}\r\n{\"name\": \"" + long + "\", \"id\": 3}",
+			expected: []streamRecord{
+				{line: 2, record: map[string]interface{}{"name": "harry", "id": int64(1)}},
+				{line: 4, record: map[string]interface{}{"name": "ron", "id": int64(2)}},
+				{line: 5, record: map[string]interface{}{"name": long, "id": int64(3)}},
+			},
+		},
+		{
+			input: "{\"name\": \"harry\", \"id\": 1}\n{\"name\": \"ron\"\n{\"name\": \"hermione\", \"id\": \"3\"}\n{\"name\": \"ginny\", \"id\": 4}\n",
+			expected: []streamRecord{
+				{line: 1, record: map[string]interface{}{"name": "harry", "id": int64(1)}},
+				{line: 2, err: `record at line 2: field parse error, path: "$", error: unmarshall record failed: unexpected end of JSON input`},
+				{line: 3, err: `record at line 3: field parse error, path: "$.id", error: value "3" in field "id" in not of type "long"`},
+				{line: 4, record: map[string]interface{}{"name": "ginny", "id": int64(4)}},
+			},
+		},
+		{
+			input: "[\n  {\"name\": \"harry\", \"id\": 1},\n  {\n    \"name\": \"r[o]n {\\\"weasley\\\"}\",\n    \"id\": 2\n  } , 3,\n\"dobby\", {\"name\": \"" + long + "\", \"id\": 4}\n]\n",
+			expected: []streamRecord{
+				{line: 2, record: map[string]interface{}{"name": "harry", "id": int64(1)}},
+				{line: 3, record: map[string]interface{}{"name": "r[o]n {\"weasley\"}", "id": int64(2)}},
+				{line: 6, err: `record at line 6: field parse error, path: "$", error: unmarshall record failed: json: cannot unmarshal number into Go value of type map[string]interface {}`},
+				{line: 7, err: `record at line 7: field parse error, path: "$", error: unmarshall record failed: json: cannot unmarshal string into Go value of type map[string]interface {}`},
+				{line: 7, record: map[string]interface{}{"name": long, "id": int64(4)}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		result, err := readStream(t, test.input)
+		assert.NoError(t, err, test.input)
+		assert.Equal(t, len(test.expected), len(result), test.input)
+		for i := range test.expected {
+			if i >= len(result) {
+				break
+			}
+			assert.Equal(t, test.expected[i].line, result[i].line, test.input)
+			assert.Equal(t, test.expected[i].err, result[i].err, test.input)
+			if test.expected[i].record != nil {
+				assert.Equal(t, test.expected[i].record, result[i].record, test.input)
+			}
+		}
+	}
+}
+
+func TestRecordReaderErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		records int
+		err     string
+	}{
+		{input: "[", err: "invalid JSON array at line 1: unexpected end of the array"},
+		{input: "[\n{\"name\": \"harry\", \"id\": 1}", records: 1, err: "invalid JSON array at line 2: unexpected end of the array"},
+		{input: "[\n{\"name\": \"harry\",\n\"id\": 1", err: "invalid JSON array at line 3: unexpected end of the record starting at line 2"},
+		{input: "[{\"name\": \"harry\", \"id\": 1}\n{\"name\": \"ron\", \"id\": 2}]", records: 1, err: "invalid JSON array at line 2: expected ',' or ']', found '{'"},
+		{input: "[{\"name\": \"harry\", \"id\": 1},]", records: 1, err: "invalid JSON array at line 1: expected a record, found ']'"},
+		{input: "[{\"name\": \"harry\", \"id\": 1}]\n[]", records: 1, err: "invalid JSON array at line 2: unexpected data after the end of the array"},
+	}
+
+	for _, test := range tests {
+		result, err := readStream(t, test.input)
+		assert.EqualError(t, err, test.err, test.input)
+		assert.Equal(t, test.records, len(result), test.input)
+	}
+}
@@ -0,0 +1,177 @@
+package kedavro
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ouzi-dev/avro-kedavro/pkg/types"
+)
+
+// appendTextual appends the avro JSON encoding of the native value of a field
+func appendTextual(buf []byte, field *Field, value interface{}) ([]byte, error) {
+	switch field.Type {
+	case types.Union:
+		return appendUnionTextual(buf, field, value)
+	case types.Primitive:
+		return appendPrimitiveTextual(buf, field, field.TypeValue.(string), value)
+	default:
+		return nil, fmt.Errorf("unknown field type in field %s", field.Name)
+	}
+}
+
+func appendUnionTextual(buf []byte, field *Field, value interface{}) ([]byte, error) {
+	if value == nil {
+		return append(buf, "null"...), nil
+	}
+
+	branch := getUnionValueType(field)
+	union, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot encode value \"%v\" of field \"%s\" as \"union\"", value, field.Name)
+	}
+
+	// unions are encoded as an object with the type of the value as key
+	buf = append(appendJSONString(append(buf, '{'), branch), ':')
+	buf, err := appendTextual(buf, getUnionBranchField(field, branch), union[branch])
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, '}'), nil
+}
+
+// nolint gocyclo
+func appendPrimitiveTextual(buf []byte, field *Field, typeName string, value interface{}) ([]byte, error) {
+	var ok bool
+	switch typeName {
+	case types.NilType:
+		if value == nil {
+			return append(buf, "null"...), nil
+		}
+	case types.BoolType:
+		var v bool
+		if v, ok = value.(bool); ok {
+			return strconv.AppendBool(buf, v), nil
+		}
+	case types.IntType:
+		var v int32
+		if v, ok = value.(int32); ok {
+			return strconv.AppendInt(buf, int64(v), 10), nil
+		}
+	case types.LongType:
+		if field.LogicalType == types.TimestampMillis || field.LogicalType == types.TimestampMicros {
+			var t time.Time
+			if t, ok = value.(time.Time); ok {
+				return strconv.AppendInt(buf, getTimestampAsLong(field, t), 10), nil
+			}
+			break
+		}
+		var v int64
+		if v, ok = value.(int64); ok {
+			return strconv.AppendInt(buf, v, 10), nil
+		}
+	case types.FloatType:
+		var v float32
+		if v, ok = value.(float32); ok {
+			return appendFloatTextual(buf, float64(v), 32), nil
+		}
+	case types.DoubleType:
+		var v float64
+		if v, ok = value.(float64); ok {
+			return appendFloatTextual(buf, v, 64), nil
+		}
+	case types.StringType:
+		var v string
+		if v, ok = value.(string); ok {
+			return appendJSONString(buf, v), nil
+		}
+	case types.BytesType:
+		var v []byte
+		if v, ok = value.([]byte); ok {
+			return appendBytesTextual(buf, v), nil
+		}
+	case types.RecordType:
+		var v map[string]interface{}
+		if v, ok = value.(map[string]interface{}); ok {
+			return appendRecordTextual(buf, field, v)
+		}
+	case types.MapType:
+		var v map[string]interface{}
+		if v, ok = value.(map[string]interface{}); ok {
+			return appendMapTextual(buf, field, v)
+		}
+	}
+
+	return nil, fmt.Errorf("cannot encode value \"%v\" of field \"%s\" as \"%s\"", value, field.Name, typeName)
+}
+
+func appendRecordTextual(buf []byte, field *Field, record map[string]interface{}) ([]byte, error) {
+	var err error
+	buf = append(buf, '{')
+	for i, v := range field.Fields {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(appendJSONString(buf, v.Name), ':')
+		if buf, err = appendTextual(buf, v, record[v.Name]); err != nil {
+			return nil, err
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+func appendMapTextual(buf []byte, field *Field, values map[string]interface{}) ([]byte, error) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	buf = append(buf, '{')
+	for i, k := range keys {
+		v, ok := values[k].(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot encode value \"%v\" of key \"%s\" in field \"%s\" as \"string\"", values[k], k, field.Name)
+		}
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(appendJSONString(buf, k), ':')
+		buf = appendJSONString(buf, v)
+	}
+	return append(buf, '}'), nil
+}
+
+// appendFloatTextual encodes floats the same way goavro does, JSON doesn't
+// support NaN or infinity
+func appendFloatTextual(buf []byte, value float64, bitSize int) []byte {
+	switch {
+	case math.IsNaN(value):
+		return append(buf, "null"...)
+	case math.IsInf(value, 1):
+		return append(buf, "1e999"...)
+	case math.IsInf(value, -1):
+		return append(buf, "-1e999"...)
+	default:
+		return strconv.AppendFloat(buf, value, 'g', -1, bitSize)
+	}
+}
+
+// appendBytesTextual encodes bytes as a string where every byte is the code
+// point with the same value, as the avro spec says
+func appendBytesTextual(buf []byte, value []byte) []byte {
+	runes := make([]rune, 0, len(value))
+	for _, b := range value {
+		runes = append(runes, rune(b))
+	}
+	return appendJSONString(buf, string(runes))
+}
+
+func appendJSONString(buf []byte, value string) []byte {
+	// marshalling a string never fails
+	b, _ := json.Marshal(value)
+	return append(buf, b...)
+}
@@ -0,0 +1,65 @@
+package kedavro
+
+import (
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseToTextual(t *testing.T) {
+	codec, err := goavro.NewCodec(binarySchema)
+	assert.NoError(t, err)
+
+	p, err := NewParser(binarySchema, WithStringToNumber())
+	assert.NoError(t, err)
+
+	records := []string{
+		`{"name": "harry \"the boy who lived\" <potter>", "alive": true, "wand": "holly ÿ\u0001", "power": 1.5, "score": -1234.5678, "age": -17, "points": 9223372036854775807, "nothing": null, "born": 1571128870123, "seen": 1571128870123456, "house": "gryffindor", "pet": null, "friends": -2, "school": {"name": "hogwarts", "year": 7}}`,
+		`{"name": "", "alive": false, "wand": "", "power": 0.1, "score": 1e-300, "age": 2147483647, "points": "-9223372036854775808", "nothing": null, "born": 0, "seen": -1, "house": null, "friends": null, "address": {"street": "", "number": -2147483648}, "school": {"name": "hogwarts", "year": null}, "owl": "hedwig", "rat": {"name": "scabbers"}}`,
+	}
+
+	for _, v := range records {
+		native, err := p.Parse([]byte(v))
+		assert.NoError(t, err, v)
+
+		expected, err := codec.TextualFromNative(nil, native)
+		assert.NoError(t, err, v)
+
+		result, err := p.ParseToTextual(nil, []byte(v))
+		assert.NoError(t, err, v)
+		assert.JSONEq(t, string(expected), string(result), v)
+
+		// and goavro can read it back
+		_, _, err = codec.NativeFromTextual(result)
+		assert.NoError(t, err, v)
+	}
+}
+
+func TestParseToTextualFormat(t *testing.T) {
+	schema := `
+	{
+		"name": "Wizard",
+		"type": "record",
+		"fields": [
+			{"name": "name", "type": ["null", "string"]},
+			{"name": "id", "type": "long"},
+			{"name": "timestamp", "type": "long", "logicalType": "timestamp-millis"},
+			{"name": "wand", "type": "bytes"},
+			{"name": "power", "type": "float"}
+		]
+	}
+	`
+
+	p, err := NewParser(schema, WithStringToNumber(), WithTimestampToMillis())
+	assert.NoError(t, err)
+
+	result, err := p.ParseToTextual([]byte("prefix "), []byte(`{"name": "Voldemort", "id": "66666", "timestamp": "1571128870", "wand": "yewé", "power": 0.1}`))
+	assert.NoError(t, err)
+	// every byte of "é" in UTF-8 is a code point
+	assert.Equal(t, "prefix {\"name\":{\"string\":\"Voldemort\"},\"id\":66666,\"timestamp\":1571128870000,\"wand\":\"yew\u00c3\u00a9\",\"power\":0.1}", string(result))
+
+	result, err = p.ParseToTextual([]byte("prefix"), []byte(`{"id": "one"}`))
+	assert.Error(t, err)
+	assert.Equal(t, "prefix", string(result))
+}
@@ -7,13 +7,13 @@ import (
 	"github.com/ouzi-dev/avro-kedavro/pkg/types"
 )
 
-func parseUnionField(field *Field, record map[string]interface{}) (interface{}, error) {
+func parseUnionField(field *Field, record map[string]interface{}, state *parseState) (interface{}, error) {
 	/*
 	 * How to deal with unions the easy way:
-	 * for now we have only unions ["null", "supported_type"] where the default can only be null
-	 * so, if we don't have value or it's nil we just return nil
-	 * though we could treat it as a nil field with default value if the union has a default value
-	 * And if it's a different type, it's a field of that type without default
+	 * for now we have only unions with two types where one of them is "null"
+	 * so, if we don't have value we use the default value, already wrapped as a union
+	 * of the first type, and if it's nil we just return nil
+	 * And if it's a different type, it's a field of the type that is not "null" without default
 	 * This should work to support unions with multiple types just checking the type of the current
 	 * value to match the first type possible in the types array
 	 */
@@ -21,44 +21,61 @@ func parseUnionField(field *Field, record map[string]interface{}) (interface{},
 	value, ok := record[field.Name]
 	if !ok {
 		if !field.HasDefault {
-			return nil, fmt.Errorf("value for field \"%s\" not found", field.Name)
+			return nil, newReasonError(ReasonMissingValue, nil, fmt.Errorf("value for field \"%s\" not found", field.Name))
 		}
-		// we already validate this... so defaultValue is null here
-		return nil, nil
+		if field.HasNativeDefault {
+			return copyNativeValue(field.NativeDefault), nil
+		}
+		return getNativeDefaultValue(field, field.DefaultValue, field.Name)
 	}
 
 	// so we have something, for now only two options, so let's check null first
 	if value == nil {
+		state.explainBranch(types.NilType)
 		return nil, nil
 	}
 
-	// now, it's not null... so it has to be of type field.TypeValue[1]!
+	// now, it's not null... so it has to be of the other type in the union!
 	// so let's create a new field!
 	// we can do this safely cause we already validated this on the package schema
+	searchedType := getUnionValueType(field)
+	unionField := getUnionBranchField(field, searchedType)
+	state.explainBranch(searchedType)
+
+	parsedValue, err := parsePrimitiveField(unionField, record, state)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return goavro.Union(searchedType, parsedValue), nil
+}
+
+// getUnionValueType returns the type in the union that is not "null"
+func getUnionValueType(field *Field) string {
 	typeArray := field.TypeValue.([]interface{})
-	searchedType := typeArray[1].(string)
-	parseFunction, _ := getParseFieldFunction(searchedType)
-	unionField := &Field{
+	if typeArray[0] == types.NilType {
+		return typeArray[1].(string)
+	}
+	return typeArray[0].(string)
+}
+
+// getUnionBranchField returns a field to parse the values of one of the types in the union
+func getUnionBranchField(field *Field, branchType string) *Field {
+	parseFunction, _ := getParseFieldFunction(branchType)
+	return &Field{
 		Name:      field.Name,
 		Type:      types.Primitive,
-		TypeValue: searchedType,
+		TypeValue: branchType,
 		// TODO: support logicaltypes in unions, for now leave it
 		// this is wrong we should here searchedType["logicaltype"]
 		LogicalType: field.LogicalType,
 		// TODO: support record type in unions
 		// same as before, I think this should be something like searchedType["fields"]
 		Fields: []*Field{},
-		// only the first item of the union can have default
+		// the default of the union is already handled by the union field
 		HasDefault: false,
 		ParseField: parseFunction,
 		Opts:       field.Opts,
 	}
-
-	parsedValue, err := parsePrimitiveField(unionField, record)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return goavro.Union(searchedType, parsedValue), nil
 }
@@ -27,6 +27,17 @@ const unionDefaultNull = `
 }
 `
 
+const unionDefaultString = `
+{
+	"name": "test",
+	"type": [
+	  "string",
+	  "null"
+	],
+	"default": "unknown"
+}
+`
+
 const jsonWithNullUnion = `
 {"test":null}
 `
@@ -100,10 +111,34 @@ func TestUnion(t *testing.T) {
 			isError:  false,
 			expected: nil,
 		},
+		{
+			field:    getFieldFromJSON(unionDefaultString, t),
+			record:   getJSONAsNative(jsonWithStringUnion, t),
+			isError:  false,
+			expected: expectedRecordWithString,
+		},
+		{
+			field:    getFieldFromJSON(unionDefaultString, t),
+			record:   getJSONAsNative(jsonWithNullUnion, t),
+			isError:  false,
+			expected: nil,
+		},
+		{
+			field:    getFieldFromJSON(unionDefaultString, t),
+			record:   getJSONAsNative(jsonWithNumberUnion, t),
+			isError:  true,
+			expected: nil,
+		},
+		{
+			field:    getFieldFromJSON(unionDefaultString, t),
+			record:   getJSONAsNative(jsonNoFieldUnion, t),
+			isError:  false,
+			expected: map[string]interface{}{"string": "unknown"},
+		},
 	}
 
 	for _, v := range tests {
-		result, err := parseUnionField(v.field, v.record)
+		result, err := parseUnionField(v.field, v.record, nil)
 		if v.isError {
 			assert.Error(t, err)
 		} else {
@@ -0,0 +1,242 @@
+package kedavro
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec is the compression codec of the blocks of an object container file
+type Codec string
+
+const (
+	CodecNull      Codec = "null"
+	CodecDeflate   Codec = "deflate"
+	CodecSnappy    Codec = "snappy"
+	CodecZstandard Codec = "zstandard"
+)
+
+const (
+	ocfMagic            = "Obj\x01"
+	ocfSyncLength       = 16
+	ocfSchemaMetadata   = "avro.schema"
+	ocfCodecMetadata    = "avro.codec"
+	defaultOCFBlockSize = 64 * 1024
+)
+
+// Writer appends records to an avro object container file
+type Writer struct {
+	w          io.Writer
+	parser     Parser
+	codec      Codec
+	blockSize  int
+	sync       []byte
+	block      []byte
+	blockCount int64
+	compressed bytes.Buffer
+	zstd       *zstd.Encoder
+}
+
+type writerOptions struct {
+	codec     Codec
+	blockSize int
+}
+
+// WriterOption reconfigure the writer creation.
+type WriterOption func(*writerOptions)
+
+func WithCodec(codec Codec) WriterOption {
+	return func(o *writerOptions) { o.codec = codec }
+}
+
+func WithBlockSize(size int) WriterOption {
+	return func(o *writerOptions) { o.blockSize = size }
+}
+
+// NewWriter writes the header of an object container file with the standard
+// avro schema of the parser to w, records written to the returned Writer are
+// buffered in blocks until they reach the block size or the writer is flushed
+func NewWriter(w io.Writer, parser Parser, opts ...WriterOption) (*Writer, error) {
+	options := writerOptions{
+		codec:     CodecNull,
+		blockSize: defaultOCFBlockSize,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.blockSize <= 0 {
+		return nil, fmt.Errorf("block size must be greater than 0, got %d", options.blockSize)
+	}
+
+	// readers of the file don't know about kedavro, so the header has the standard schema
+	schema, err := getStandardSchema(parser.Schema())
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema for object container file: %v", err)
+	}
+
+	writer := &Writer{
+		w:         w,
+		parser:    parser,
+		codec:     options.codec,
+		blockSize: options.blockSize,
+		sync:      make([]byte, ocfSyncLength),
+	}
+
+	switch options.codec {
+	case CodecNull, CodecDeflate, CodecSnappy:
+	case CodecZstandard:
+		encoder, err := zstd.NewWriter(nil)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create zstandard encoder: %v", err)
+		}
+		writer.zstd = encoder
+	default:
+		return nil, fmt.Errorf("unsupported codec \"%s\"", options.codec)
+	}
+
+	if _, err := rand.Read(writer.sync); err != nil {
+		return nil, fmt.Errorf("cannot generate sync marker: %v", err)
+	}
+
+	if err := writer.writeHeader(schema); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// Write parses a JSON record and appends it to the current block, a record
+// that can't be parsed is not written
+func (w *Writer) Write(record []byte) error {
+	jsonRecord, err := unmarshalRecord(record)
+	if err != nil {
+		return err
+	}
+
+	return w.WriteMap(jsonRecord)
+}
+
+// WriteMap parses a record and appends it to the current block, a record that
+// can't be parsed is not written
+func (w *Writer) WriteMap(record map[string]interface{}) error {
+	block, err := w.parser.ParseMapToBinary(w.block, record)
+	if err != nil {
+		return err
+	}
+
+	w.block = block
+	w.blockCount++
+
+	if len(w.block) >= w.blockSize {
+		return w.Flush()
+	}
+
+	return nil
+}
+
+// Flush writes the records of the current block to the underlying writer
+func (w *Writer) Flush() error {
+	if w.blockCount == 0 {
+		return nil
+	}
+
+	data, err := w.compress(w.block)
+	if err != nil {
+		return err
+	}
+
+	buf := appendLong(nil, w.blockCount)
+	buf = appendLong(buf, int64(len(data)))
+	buf = append(buf, data...)
+	buf = append(buf, w.sync...)
+
+	if _, err := w.w.Write(buf); err != nil {
+		return fmt.Errorf("cannot write block: %v", err)
+	}
+
+	w.block = w.block[:0]
+	w.blockCount = 0
+
+	return nil
+}
+
+// Close flushes the current block, the underlying writer is not closed
+func (w *Writer) Close() error {
+	err := w.Flush()
+
+	if w.zstd != nil {
+		if closeErr := w.zstd.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("cannot close zstandard encoder: %v", closeErr)
+		}
+	}
+
+	return err
+}
+
+func (w *Writer) writeHeader(schema string) error {
+	metadata := map[string][]byte{
+		ocfSchemaMetadata: []byte(schema),
+		ocfCodecMetadata:  []byte(w.codec),
+	}
+
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	buf := []byte(ocfMagic)
+	buf = appendLong(buf, int64(len(keys)))
+	for _, k := range keys {
+		buf = appendLong(buf, int64(len(k)))
+		buf = append(buf, k...)
+		buf = appendLong(buf, int64(len(metadata[k])))
+		buf = append(buf, metadata[k]...)
+	}
+	buf = appendLong(buf, 0)
+	buf = append(buf, w.sync...)
+
+	if _, err := w.w.Write(buf); err != nil {
+		return fmt.Errorf("cannot write header: %v", err)
+	}
+
+	return nil
+}
+
+func (w *Writer) compress(data []byte) ([]byte, error) {
+	switch w.codec {
+	case CodecDeflate:
+		w.compressed.Reset()
+		fw, err := flate.NewWriter(&w.compressed, flate.DefaultCompression)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create deflate writer: %v", err)
+		}
+		if _, err := fw.Write(data); err != nil {
+			return nil, fmt.Errorf("cannot compress block: %v", err)
+		}
+		if err := fw.Close(); err != nil {
+			return nil, fmt.Errorf("cannot compress block: %v", err)
+		}
+		return w.compressed.Bytes(), nil
+	case CodecSnappy:
+		// snappy blocks are followed by the CRC32 checksum of the uncompressed data
+		compressed := snappy.Encode(nil, data)
+		checksum := make([]byte, 4)
+		binary.BigEndian.PutUint32(checksum, crc32.ChecksumIEEE(data))
+		return append(compressed, checksum...), nil
+	case CodecZstandard:
+		return w.zstd.EncodeAll(data, nil), nil
+	default:
+		return data, nil
+	}
+}
@@ -0,0 +1,226 @@
+package kedavro
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+const writerSchema = `
+{
+	"name": "Wizard",
+	"type": "record",
+	"fields": [
+		{"name": "name", "type": "string"},
+		{"name": "house", "type": ["null", "string"]},
+		{"name": "born", "type": "long", "logicalType": "timestamp-millis"}
+	]
+}
+`
+
+var writerRecords = []string{
+	`{"name": "harry", "house": "gryffindor", "born": 1571128870000}`,
+	`{"name": "draco", "house": "slytherin", "born": 1571128870001}`,
+	`{"name": "dobby", "house": null, "born": 1571128870002}`,
+}
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		codec     Codec
+		blockSize int
+	}{
+		{codec: CodecNull, blockSize: 1},
+		{codec: CodecNull, blockSize: 1024},
+		{codec: CodecDeflate, blockSize: 1},
+		{codec: CodecDeflate, blockSize: 1024},
+		{codec: CodecSnappy, blockSize: 1},
+		{codec: CodecSnappy, blockSize: 1024},
+	}
+
+	p, err := NewParser(writerSchema)
+	assert.NoError(t, err)
+
+	for _, test := range tests {
+		out := &bytes.Buffer{}
+		w, err := NewWriter(out, p, WithCodec(test.codec), WithBlockSize(test.blockSize))
+		assert.NoError(t, err, test.codec)
+
+		expected := [][]byte{}
+		for _, v := range writerRecords {
+			assert.NoError(t, w.Write([]byte(v)), test.codec)
+			encoded, err := p.ParseToBinary(nil, []byte(v))
+			assert.NoError(t, err)
+			expected = append(expected, encoded)
+		}
+		assert.NoError(t, w.Close(), test.codec)
+
+		reader, err := goavro.NewOCFReader(out)
+		assert.NoError(t, err, test.codec)
+		assert.Equal(t, string(test.codec), reader.CompressionName())
+
+		result := [][]byte{}
+		for reader.Scan() {
+			record, err := reader.Read()
+			assert.NoError(t, err, test.codec)
+			encoded, err := reader.Codec().BinaryFromNative(nil, record)
+			assert.NoError(t, err, test.codec)
+			result = append(result, encoded)
+		}
+		assert.NoError(t, reader.Err(), test.codec)
+		assert.Equal(t, expected, result, test.codec)
+	}
+}
+
+func TestWriterZstandard(t *testing.T) {
+	p, err := NewParser(writerSchema)
+	assert.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	w, err := NewWriter(out, p, WithCodec(CodecZstandard))
+	assert.NoError(t, err)
+
+	expected := []byte{}
+	for _, v := range writerRecords {
+		assert.NoError(t, w.Write([]byte(v)))
+		expected, err = p.ParseToBinary(expected, []byte(v))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+
+	// goavro doesn't support zstandard, so the header and the block are read by hand
+	metadataCodec, err := goavro.NewCodec(`{"type": "map", "values": "bytes"}`)
+	assert.NoError(t, err)
+	longCodec, err := goavro.NewCodec(`"long"`)
+	assert.NoError(t, err)
+
+	data := out.Bytes()
+	assert.Equal(t, []byte("Obj\x01"), data[:4])
+
+	metadata, data, err := metadataCodec.NativeFromBinary(data[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("zstandard"), metadata.(map[string]interface{})["avro.codec"])
+	assert.Equal(t, []byte(`{"fields":[{"name":"name","type":"string"},{"name":"house","type":["null","string"]},{"name":"born","type":{"logicalType":"timestamp-millis","type":"long"}}],"name":"Wizard","type":"record"}`), metadata.(map[string]interface{})["avro.schema"])
+	sync, data := data[:16], data[16:]
+
+	count, data, err := longCodec.NativeFromBinary(data)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(writerRecords)), count)
+
+	size, data, err := longCodec.NativeFromBinary(data)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(data)-16), size)
+	assert.Equal(t, sync, data[len(data)-16:])
+
+	decoder, err := zstd.NewReader(nil)
+	assert.NoError(t, err)
+	defer decoder.Close()
+
+	block, err := decoder.DecodeAll(data[:len(data)-16], nil)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, block)
+}
+
+func TestWriterNestedRecord(t *testing.T) {
+	schema := `
+	{
+		"name": "Wizard",
+		"type": "record",
+		"fields": [
+			{"name": "name", "type": "string", "aliases": ["Name"]},
+			{
+				"name": "wand",
+				"type": "record",
+				"kedavro.onError": "default",
+				"default": {"wood": "holly", "length": 11},
+				"fields": [
+					{"name": "wood", "type": "string", "kedavro.source": "$.material"},
+					{"name": "length", "type": "int"}
+				]
+			},
+			{"name": "extra", "type": {"type": "map", "values": "string"}, "kedavro.catchAll": true}
+		]
+	}
+	`
+
+	p, err := NewParser(schema)
+	assert.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	w, err := NewWriter(out, p)
+	assert.NoError(t, err)
+
+	assert.NoError(t, w.Write([]byte(`{"Name": "harry", "wand": {"material": "holly", "length": 11}}`)))
+	assert.NoError(t, w.Write([]byte(`{"name": "draco", "wand": {"material": "hawthorn", "length": 10}, "house": "slytherin"}`)))
+	assert.NoError(t, w.Close())
+
+	// the header has the standard schema, without the kedavro attributes and with the record as type of the field
+	metadataCodec, err := goavro.NewCodec(`{"type": "map", "values": "bytes"}`)
+	assert.NoError(t, err)
+	metadata, _, err := metadataCodec.NativeFromBinary(out.Bytes()[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, `{"fields":[{"name":"name","type":"string"},{"default":{"length":11,"wood":"holly"},"name":"wand","type":{"fields":[{"name":"wood","type":"string"},{"name":"length","type":"int"}],"name":"wand","type":"record"}},{"name":"extra","type":{"type":"map","values":"string"}}],"name":"Wizard","type":"record"}`, string(metadata.(map[string]interface{})["avro.schema"].([]byte)))
+
+	reader, err := goavro.NewOCFReader(out)
+	assert.NoError(t, err)
+
+	expected := []interface{}{
+		map[string]interface{}{
+			"name":  "harry",
+			"wand":  map[string]interface{}{"wood": "holly", "length": int32(11)},
+			"extra": map[string]interface{}{},
+		},
+		map[string]interface{}{
+			"name":  "draco",
+			"wand":  map[string]interface{}{"wood": "hawthorn", "length": int32(10)},
+			"extra": map[string]interface{}{"house": `"slytherin"`},
+		},
+	}
+
+	result := []interface{}{}
+	for reader.Scan() {
+		record, err := reader.Read()
+		assert.NoError(t, err)
+		result = append(result, record)
+	}
+	assert.NoError(t, reader.Err())
+	assert.Equal(t, expected, result)
+}
+
+func TestWriterErrors(t *testing.T) {
+	p, err := NewParser(writerSchema)
+	assert.NoError(t, err)
+
+	_, err = NewWriter(&bytes.Buffer{}, p, WithCodec("bzip2"))
+	assert.EqualError(t, err, `unsupported codec "bzip2"`)
+
+	_, err = NewWriter(&bytes.Buffer{}, p, WithBlockSize(0))
+	assert.EqualError(t, err, "block size must be greater than 0, got 0")
+
+	out := &bytes.Buffer{}
+	w, err := NewWriter(out, p)
+	assert.NoError(t, err)
+
+	assert.NoError(t, w.Write([]byte(writerRecords[0])))
+	err = w.Write([]byte(`{"name": "hedwig", "house": null, "born": "never"}`))
+	var parseErr *ParseError
+	assert.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, "$.born", parseErr.Path)
+	assert.NoError(t, w.Write([]byte(writerRecords[1])))
+	assert.NoError(t, w.Close())
+
+	reader, err := goavro.NewOCFReader(out)
+	assert.NoError(t, err)
+
+	count := 0
+	for reader.Scan() {
+		_, err := reader.Read()
+		assert.NoError(t, err)
+		count++
+	}
+	assert.NoError(t, reader.Err())
+	assert.Equal(t, 2, count)
+}
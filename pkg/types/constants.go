@@ -10,12 +10,13 @@ const (
 	IntType    = "int"
 	StringType = "string"
 	RecordType = "record"
+	// only supported for catch-all fields
+	MapType = "map"
 
 	// not supported yet:
 	//	arrayType = "array"
 	//	enumType  = "enum"
 	//	fixedType = "fixed"
-	//	mapType   = "map"
 
 	TimestampMillis = "timestamp-millis"
 	TimestampMicros = "timestamp-micros"
@@ -8,12 +8,45 @@ const (
 	Union     FieldType = 2
 )
 
+// ConflictPolicy decides which value to use when more than one key in the
+// record matches the same field
+type ConflictPolicy int
+
+const (
+	ConflictUseFirst ConflictPolicy = 0
+	ConflictUseLast  ConflictPolicy = 1
+	ConflictError    ConflictPolicy = 2
+)
+
+// ErrorPolicy decides what to do when the value of a field can't be parsed
+type ErrorPolicy int
+
+const (
+	ErrorPolicyFail       ErrorPolicy = 0
+	ErrorPolicyNull       ErrorPolicy = 1
+	ErrorPolicyDefault    ErrorPolicy = 2
+	ErrorPolicyDeadLetter ErrorPolicy = 3
+)
+
 type Options struct {
-	IsStringToNumber        bool
-	IsStringToBool          bool
-	IsTimestampToMillis     bool
-	IsTimestampToMicros     bool
-	IsFormatDateTime        bool
-	IsSetNowForNilTimestamp bool
-	DateTimeFormat          string
+	IsStringToNumber          bool
+	IsStringToBool            bool
+	IsTimestampToMillis       bool
+	IsTimestampToMicros       bool
+	IsFormatDateTime          bool
+	IsSetNowForNilTimestamp   bool
+	DateTimeFormat            string
+	IsRecordDefaultFromFields bool
+	AliasConflictPolicy       ConflictPolicy
+	IsNormalizeKeys           bool
+	IsUnflattenKeys           bool
+	UnflattenSeparator        string
+	IsFlattenKeys             bool
+	FlattenSeparator          string
+	IsStrictFields            bool
+	IsEmbeddedJSON            bool
+	IsAllErrors               bool
+	ErrorPolicy               ErrorPolicy
+	IsSchemaID                bool
+	SchemaID                  uint32
 }